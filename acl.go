@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ACL права потребителей: имя -> полные имена методов /package.Service/Method
+// или маски /package.Service/*
+type ACL map[string][]string
+
+// ParseACL разбирает JSON и проверяет, что каждое правило похоже на имя метода
+func ParseACL(data string) (ACL, error) {
+	acl := make(ACL)
+	if err := json.Unmarshal([]byte(data), &acl); err != nil {
+		return nil, err
+	}
+	for consumer, methods := range acl {
+		for _, m := range methods {
+			parts := strings.Split(m, "/")
+			if len(parts) != 3 || parts[0] != "" || parts[1] == "" || parts[2] == "" {
+				return nil, fmt.Errorf("consumer %q: bad method %q, want /package.Service/Method or /package.Service/*", consumer, m)
+			}
+		}
+	}
+	return acl, nil
+}
+
+// Allowed может ли consumer вызывать method. Неизвестному потребителю
+// и потребителю без методов возвращается errInvalidConsumer
+func (acl ACL) Allowed(consumer, method string) (bool, error) {
+	methods, ok := acl[consumer]
+	if !ok || len(methods) == 0 {
+		return false, errInvalidConsumer
+	}
+	for _, m := range methods {
+		fullM := strings.Split(m, "/")
+		if strings.HasSuffix(method, fullM[1]+"/"+fullM[2]) || (fullM[2] == "*" && strings.Contains(method, fullM[1])) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Consumers кому по ACL разрешён method, по алфавиту
+func (acl ACL) Consumers(method string) []string {
+	var consumers []string
+	for consumer := range acl {
+		if ok, _ := acl.Allowed(consumer, method); ok {
+			consumers = append(consumers, consumer)
+		}
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// serviceFiles описания всех сервисов, которые регистрирует NewServer
+func serviceFiles() []protoreflect.FileDescriptor {
+	return []protoreflect.FileDescriptor{
+		File_service_proto,
+		healthpb.File_grpc_health_v1_health_proto,
+		reflectionpb.File_grpc_reflection_v1_reflection_proto,
+		reflectionv1alphapb.File_grpc_reflection_v1alpha_reflection_proto,
+	}
+}
+
+// Lint предупреждения о правилах, которые разобрались, но скорее всего ошибочны:
+// потребитель без методов, повтор и имя, не совпадающее ни с одним методом сервиса
+func (acl ACL) Lint() []string {
+	known := make(map[string]bool)
+	for _, file := range serviceFiles() {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				known["/"+string(services.Get(i).FullName())+"/"+string(methods.Get(j).Name())] = true
+			}
+		}
+	}
+
+	consumers := make([]string, 0, len(acl))
+	for consumer := range acl {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+
+	var warnings []string
+	for _, consumer := range consumers {
+		if len(acl[consumer]) == 0 {
+			warnings = append(warnings, fmt.Sprintf("consumer %q has no methods and is always denied", consumer))
+		}
+		seen := make(map[string]bool)
+		for _, m := range acl[consumer] {
+			if seen[m] {
+				warnings = append(warnings, fmt.Sprintf("consumer %q: duplicate method %q", consumer, m))
+			}
+			seen[m] = true
+			matched := false
+			for method := range known {
+				if matchMethod(m, method) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				warnings = append(warnings, fmt.Sprintf("consumer %q: %q matches no method of the service", consumer, m))
+			}
+		}
+	}
+	return warnings
+}
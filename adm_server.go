@@ -2,67 +2,249 @@ package main
 
 import (
 	"context"
+	"net"
+	"sort"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// алерты считаются по кольцу SimpleEventStats, с другой реализацией EventStats их нет
+var errAlertsUnavailable = status.Errorf(codes.Unimplemented, "alerts require the built-in statistics")
+
 type AdminServ struct {
-	host   string
-	logger *SimpleEventLogger
-	stats  *SimpleEventStats
+	logger    EventLogger
+	stats     EventStats
+	alerts    *AlertManager // nil при собственной реализации EventStats
+	drain     *drainState
+	clock     Clock
+	acl       ACL
+	server    *grpc.Server
+	open      func(method string) bool // методы, которые не проверяются по ACL
+	started   time.Time
+	listeners []string
 }
 
 func (adm *AdminServ) mustEmbedUnimplementedAdminServer() {}
 
 func (adm *AdminServ) Logging(n *Nothing, logServerStream Admin_LoggingServer) error {
 	ch := adm.logger.Subscribe()
-
-	ctxTimeout, _ := context.WithTimeout(context.Background(), time.Second*3)
+	defer adm.logger.Unsubscribe(ch)
 
 	for {
 		select {
-		case <-ctxTimeout.Done():
-			adm.logger.Unsubscribe(ch)
+		case <-logServerStream.Context().Done():
 			return nil
 		case msg := <-ch:
 			err := logServerStream.Send(msg)
-			if err != nil {
-				adm.logger.Unsubscribe(ch)
+			if err != nil || msg.Kind == EventKind_EVENT_KIND_SHUTDOWN {
 				return err
 			}
+		case <-adm.drain.done:
+			// событие об остановке уже в канале, если его не вытеснил переполненный буфер
+			for {
+				select {
+				case msg := <-ch:
+					err := logServerStream.Send(msg)
+					if err != nil || msg.Kind == EventKind_EVENT_KIND_SHUTDOWN {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
 		}
 	}
-
 }
 
 func (adm *AdminServ) Statistics(interval *StatInterval, stream Admin_StatisticsServer) error {
-	ticker := time.NewTicker(time.Duration(interval.IntervalSeconds) * time.Second)
-	ch := adm.logger.Subscribe()
-	stat := adm.stats.InitStat()
+	w, err := adm.stats.Subscribe(interval)
+	if err != nil {
+		return err
+	}
+	defer adm.stats.Unsubscribe(w)
+	// окно собственной реализации EventStats без NewStatWindow: с нулевым
+	// интервалом цикл расписания ниже никогда бы не закончился
+	if w.Interval() <= 0 {
+		return status.Errorf(codes.Internal, "statistics window has no interval")
+	}
+	// расписание по реальному времени, часы сервера только ставят отметки окон,
+	// иначе с фиксированными часами окна никогда бы не сдвигались
+	next := firstTick(time.Now(), w.spec.interval, w.spec.align)
+	timer := time.NewTimer(time.Until(next))
 
-	defer ticker.Stop()
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-stream.Context().Done():
-			adm.logger.Unsubscribe(ch)
 			return stream.Context().Err()
-		case e := <-ch:
-			adm.stats.UpdateStat(e)
-		case <-ticker.C:
-			err := stream.Send(stat)
+		case <-adm.drain.done:
+			// последнее, неполное окно перед остановкой
+			return stream.Send(adm.stats.Flush(w, adm.clock.Now()))
+		case <-timer.C:
+			// окно закрывается по расписанию, а не по фактическому срабатыванию таймера,
+			// поэтому границы окон у подписчиков с align совпадают
+			err := stream.Send(adm.stats.Flush(w, adm.windowTime(next)))
 			if err != nil {
-				adm.logger.Unsubscribe(ch)
 				return err
 			}
-			adm.stats.InitStat()
+			// расписание считается от предыдущего тика, а не от момента отправки,
+			// поэтому интервал не уплывает; пропущенные тики не догоняются
+			now := time.Now()
+			for !next.After(now) {
+				next = next.Add(w.spec.interval)
+			}
+			timer.Reset(next.Sub(now))
 		}
 	}
 }
 
-func getAdminInstance(host string, logger *SimpleEventLogger, stats *SimpleEventStats) *AdminServ {
+// windowTime отметка окна для тика расписания. С SystemClock это сам тик,
+// поэтому границы окон с align совпадают точно, с другими часами - их текущее время
+func (adm *AdminServ) windowTime(tick time.Time) time.Time {
+	if _, ok := adm.clock.(SystemClock); ok {
+		return tick
+	}
+	return adm.clock.Now()
+}
+
+// firstTick при align первый тик приходится на ближайшую границу, кратную интервалу,
+// так что подписчики с одинаковым интервалом получают одинаковые окна
+func firstTick(now time.Time, interval time.Duration, align bool) time.Time {
+	if align {
+		return now.Truncate(interval).Add(interval)
+	}
+	return now.Add(interval)
+}
+
+func (adm *AdminServ) GetStatistics(ctx context.Context, q *StatQuery) (*Stat, error) {
+	return adm.stats.Query(q, adm.clock.Now())
+}
+
+func (adm *AdminServ) TopK(ctx context.Context, q *TopKQuery) (*TopKReply, error) {
+	return adm.stats.TopK(q, adm.clock.Now())
+}
+
+func (adm *AdminServ) History(ctx context.Context, q *HistoryQuery) (*HistoryReply, error) {
+	return adm.stats.History(q, adm.clock.Now())
+}
+
+func (adm *AdminServ) GetSLOs(ctx context.Context, q *SLOQuery) (*SLOReport, error) {
+	return adm.stats.SLOs(q, adm.clock.Now())
+}
+
+func (adm *AdminServ) Alerts(n *Nothing, stream Admin_AlertsServer) error {
+	if adm.alerts == nil {
+		return errAlertsUnavailable
+	}
+	ch, current := adm.alerts.Subscribe()
+	defer adm.alerts.Unsubscribe(ch)
+
+	for _, a := range current {
+		err := stream.Send(a)
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-adm.drain.done:
+			return nil
+		case a := <-ch:
+			err := stream.Send(a)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (adm *AdminServ) ListAlertRules(ctx context.Context, n *Nothing) (*AlertRules, error) {
+	if adm.alerts == nil {
+		return nil, errAlertsUnavailable
+	}
+	return adm.alerts.Rules(), nil
+}
+
+func (adm *AdminServ) SetAlertRule(ctx context.Context, r *AlertRule) (*Nothing, error) {
+	if adm.alerts == nil {
+		return nil, errAlertsUnavailable
+	}
+	return &Nothing{}, adm.alerts.SetRule(r)
+}
+
+func (adm *AdminServ) DeleteAlertRule(ctx context.Context, r *AlertRuleName) (*Nothing, error) {
+	if adm.alerts == nil {
+		return nil, errAlertsUnavailable
+	}
+	return &Nothing{}, adm.alerts.DeleteRule(r.GetName())
+}
+
+// Describe зарегистрированные сервисы с правами потребителей и состояние сервера
+func (adm *AdminServ) Describe(ctx context.Context, n *Nothing) (*Description, error) {
+	now := adm.clock.Now()
+	d := &Description{
+		Started:       timestamppb.New(adm.started),
+		UptimeSeconds: uint64(now.Sub(adm.started) / time.Second),
+		Version:       buildVersion(),
+		Listeners:     adm.listeners,
+	}
+	if lc, ok := adm.logger.(loggerCounters); ok {
+		d.LoggingSubscribers = uint32(lc.Subscribers())
+	}
+	if ss, ok := adm.stats.(*SimpleEventStats); ok {
+		d.StatisticsSubscribers = uint32(ss.Windows())
+	}
+
+	info := adm.server.GetServiceInfo()
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		service := &ServiceInfo{Name: name}
+		for _, m := range info[name].Methods {
+			method := "/" + name + "/" + m.Name
+			mi := &MethodInfo{
+				Name:            method,
+				ClientStreaming: m.IsClientStream,
+				ServerStreaming: m.IsServerStream,
+				Open:            adm.open(method),
+			}
+			if !mi.Open {
+				mi.Consumers = adm.acl.Consumers(method)
+			}
+			service.Methods = append(service.Methods, mi)
+		}
+		d.Services = append(d.Services, service)
+	}
+	return d, nil
+}
+
+func getAdminInstance(d *authDeps, alerts *AlertManager, server *grpc.Server, listeners []net.Listener) *AdminServ {
+	names := make([]string, len(listeners))
+	for i, l := range listeners {
+		names[i] = formatAddr(l.Addr())
+	}
 	return &AdminServ{
-		host:   host,
-		logger: logger,
-		stats:  stats,
+		logger: d.logger,
+		stats:  d.stats,
+		alerts: alerts,
+		drain:  d.drain,
+		clock:  d.clock,
+		acl:    d.acl,
+		server: server,
+		open: func(method string) bool {
+			return isHealthMethod(method) && !d.healthACL
+		},
+		started:   d.clock.Now(),
+		listeners: names,
 	}
 }
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// как часто пересчитываются правила
+	alertEvalInterval = time.Second
+	// запас между порогом срабатывания и порогом гашения, если resolve_threshold не задан
+	alertHysteresis = 0.1
+)
+
+// alertInstance сработавший алерт одного правила по одному набору меток
+type alertInstance struct {
+	labels map[string]string
+	since  time.Time
+	value  float64 // при последнем вычислении
+}
+
+// alertSeries значение правила по одному набору меток за окно
+type alertSeries struct {
+	labels   map[string]string
+	num, den uint64
+	sketch   *latencySketch
+}
+
+// AlertManager раз в alertEvalInterval вычисляет правила по скользящему окну
+// статистики и рассылает подписчикам Alerts переходы между firing и resolved.
+// Чтобы значение около порога не вызывало дребезга, алерт гаснет только
+// после возврата за resolve_threshold
+type AlertManager struct {
+	mu          sync.Mutex
+	stats       *SimpleEventStats
+	path        string // куда сохраняются правила после правки через Admin; пусто - не сохранять
+	rules       map[string]*AlertRule
+	firing      map[string]map[string]*alertInstance // правило -> ключ меток
+	subscribers map[chan *Alert]struct{}
+	dropped     uint64
+}
+
+// NewAlertManager читает правила из path, если файл есть
+func NewAlertManager(stats *SimpleEventStats, path string) (*AlertManager, error) {
+	am := &AlertManager{
+		stats:       stats,
+		path:        path,
+		rules:       make(map[string]*AlertRule),
+		firing:      make(map[string]map[string]*alertInstance),
+		subscribers: make(map[chan *Alert]struct{}),
+	}
+	if path == "" {
+		return am, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return am, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rules := &AlertRules{}
+	if err := protojson.Unmarshal(data, rules); err != nil {
+		return nil, err
+	}
+	for _, r := range rules.GetRules() {
+		if err := am.validate(r); err != nil {
+			return nil, err
+		}
+		am.rules[r.GetName()] = r
+	}
+	return am, nil
+}
+
+// Run пересчитывает правила до отмены ctx
+func (am *AlertManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(alertEvalInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			am.evaluate(am.stats.now())
+		}
+	}
+}
+
+// Rules правила по алфавиту имён
+func (am *AlertManager) Rules() *AlertRules {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	return am.sortedRules()
+}
+
+func (am *AlertManager) sortedRules() *AlertRules {
+	rules := &AlertRules{}
+	for _, r := range am.rules {
+		rules.Rules = append(rules.Rules, proto.Clone(r).(*AlertRule))
+	}
+	sort.Slice(rules.Rules, func(i, j int) bool {
+		return rules.Rules[i].GetName() < rules.Rules[j].GetName()
+	})
+	return rules
+}
+
+// SetRule добавляет правило или заменяет одноимённое.
+// Алерты заменённого правила гаснут и пересчитываются заново
+func (am *AlertManager) SetRule(r *AlertRule) error {
+	if err := am.validate(r); err != nil {
+		return err
+	}
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.resolveAll(r.GetName(), am.stats.now())
+	am.rules[r.GetName()] = proto.Clone(r).(*AlertRule)
+	return am.save()
+}
+
+// DeleteRule удаляет правило, его сработавшие алерты гаснут
+func (am *AlertManager) DeleteRule(name string) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if _, ok := am.rules[name]; !ok {
+		return status.Errorf(codes.NotFound, "no alert rule %q", name)
+	}
+	am.resolveAll(name, am.stats.now())
+	delete(am.rules, name)
+	return am.save()
+}
+
+// Subscribe канал переходов и текущие сработавшие алерты, чтобы
+// подписчик знал состояние, не дожидаясь следующего перехода
+func (am *AlertManager) Subscribe() (chan *Alert, []*Alert) {
+	ch := make(chan *Alert, subscriberBuffer)
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.subscribers[ch] = struct{}{}
+
+	var current []*Alert
+	now := am.stats.now()
+	for name, instances := range am.firing {
+		for _, inst := range instances {
+			current = append(current, am.alert(name, inst, AlertState_ALERT_STATE_FIRING, inst.value, now))
+		}
+	}
+	return ch, current
+}
+
+func (am *AlertManager) Unsubscribe(ch chan *Alert) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	delete(am.subscribers, ch)
+}
+
+// Dropped сколько алертов не было доставлено переполненным подписчикам
+func (am *AlertManager) Dropped() uint64 {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	return am.dropped
+}
+
+func (am *AlertManager) validate(r *AlertRule) error {
+	if r.GetName() == "" {
+		return status.Errorf(codes.InvalidArgument, "alert rule name is required")
+	}
+	window := time.Duration(r.GetWindowSeconds()) * time.Second
+	if window <= 0 || window > am.stats.cfg.MaxWindow {
+		return status.Errorf(codes.InvalidArgument, "rule %q: window_seconds must be in (0, %d]", r.GetName(), int64(am.stats.cfg.MaxWindow/time.Second))
+	}
+	for _, g := range r.GetGroupBy() {
+		if g != GroupBy_GROUP_BY_CONSUMER && g != GroupBy_GROUP_BY_METHOD {
+			return status.Errorf(codes.InvalidArgument, "rule %q: only consumer and method grouping is supported", r.GetName())
+		}
+		if g == GroupBy_GROUP_BY_CONSUMER && r.GetMetric() == AlertMetric_ALERT_METRIC_LATENCY_P99 {
+			return status.Errorf(codes.InvalidArgument, "rule %q: latency is only tracked by method", r.GetName())
+		}
+	}
+	switch r.GetMetric() {
+	case AlertMetric_ALERT_METRIC_CALLS, AlertMetric_ALERT_METRIC_ERROR_RATIO:
+	case AlertMetric_ALERT_METRIC_LATENCY_P99:
+		if r.GetConsumer() != "" {
+			return status.Errorf(codes.InvalidArgument, "rule %q: latency is only tracked by method", r.GetName())
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "rule %q: unknown metric %v", r.GetName(), r.GetMetric())
+	}
+	switch r.GetOp() {
+	case AlertOp_ALERT_OP_GREATER:
+		if r.GetResolveThreshold() > r.GetThreshold() {
+			return status.Errorf(codes.InvalidArgument, "rule %q: resolve_threshold must not exceed threshold", r.GetName())
+		}
+	case AlertOp_ALERT_OP_LESS:
+		if r.GetResolveThreshold() != 0 && r.GetResolveThreshold() < r.GetThreshold() {
+			return status.Errorf(codes.InvalidArgument, "rule %q: resolve_threshold must not be below threshold", r.GetName())
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "rule %q: unknown op %v", r.GetName(), r.GetOp())
+	}
+	return nil
+}
+
+func (am *AlertManager) evaluate(now time.Time) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	// правила с одинаковым окном читают одну копию кольца
+	accs := make(map[uint64]*statAcc)
+	for name, r := range am.rules {
+		acc, ok := accs[r.GetWindowSeconds()]
+		if !ok {
+			acc = am.stats.since(now, time.Duration(r.GetWindowSeconds())*time.Second)
+			accs[r.GetWindowSeconds()] = acc
+		}
+
+		instances := am.firing[name]
+		if instances == nil {
+			instances = make(map[string]*alertInstance)
+			am.firing[name] = instances
+		}
+		series := ruleSeries(r, acc)
+		for key, s := range series {
+			v := s.value(r.GetMetric())
+			if inst, ok := instances[key]; ok {
+				inst.value = v
+				if alertResolved(r, v) {
+					delete(instances, key)
+					am.publish(am.alert(name, inst, AlertState_ALERT_STATE_RESOLVED, v, now))
+				}
+			} else if alertFires(r, v) {
+				inst := &alertInstance{labels: s.labels, since: now, value: v}
+				instances[key] = inst
+				am.publish(am.alert(name, inst, AlertState_ALERT_STATE_FIRING, v, now))
+			}
+		}
+		// ключ без вызовов в окне считается нулевым
+		for key, inst := range instances {
+			if _, ok := series[key]; !ok && alertResolved(r, 0) {
+				delete(instances, key)
+				am.publish(am.alert(name, inst, AlertState_ALERT_STATE_RESOLVED, 0, now))
+			}
+		}
+	}
+}
+
+func (am *AlertManager) resolveAll(name string, now time.Time) {
+	for _, inst := range am.firing[name] {
+		am.publish(am.alert(name, inst, AlertState_ALERT_STATE_RESOLVED, inst.value, now))
+	}
+	delete(am.firing, name)
+}
+
+func (am *AlertManager) alert(name string, inst *alertInstance, state AlertState, value float64, now time.Time) *Alert {
+	r := am.rules[name]
+	threshold := r.GetThreshold()
+	if state == AlertState_ALERT_STATE_RESOLVED {
+		threshold = resolveThreshold(r)
+	}
+	return &Alert{
+		Rule:      name,
+		State:     state,
+		Labels:    inst.labels,
+		Value:     value,
+		Threshold: threshold,
+		Time:      timestamppb.New(now),
+		Since:     timestamppb.New(inst.since),
+	}
+}
+
+func (am *AlertManager) publish(a *Alert) {
+	for sub := range am.subscribers {
+		select {
+		case sub <- a:
+		default:
+			am.dropped++
+		}
+	}
+}
+
+func (am *AlertManager) save() error {
+	if am.path == "" {
+		return nil
+	}
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(am.sortedRules())
+	if err != nil {
+		return err
+	}
+	tmp := am.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, am.path)
+}
+
+func resolveThreshold(r *AlertRule) float64 {
+	if r.GetResolveThreshold() != 0 {
+		return r.GetResolveThreshold()
+	}
+	if r.GetOp() == AlertOp_ALERT_OP_LESS {
+		return r.GetThreshold() * (1 + alertHysteresis)
+	}
+	return r.GetThreshold() * (1 - alertHysteresis)
+}
+
+func alertFires(r *AlertRule, v float64) bool {
+	if r.GetOp() == AlertOp_ALERT_OP_LESS {
+		return v < r.GetThreshold()
+	}
+	return v > r.GetThreshold()
+}
+
+func alertResolved(r *AlertRule, v float64) bool {
+	if r.GetOp() == AlertOp_ALERT_OP_LESS {
+		return v >= resolveThreshold(r)
+	}
+	return v <= resolveThreshold(r)
+}
+
+// ruleSeries раскладывает окно по наборам меток правила.
+// Без group_by ряд один и есть всегда, поэтому правило с ALERT_OP_LESS
+// срабатывает и при полном отсутствии вызовов; ряды по группам появляются
+// только для потребителей и методов, у которых были вызовы в окне
+func ruleSeries(r *AlertRule, acc *statAcc) map[string]*alertSeries {
+	var byConsumer, byMethod bool
+	for _, g := range r.GetGroupBy() {
+		byConsumer = byConsumer || g == GroupBy_GROUP_BY_CONSUMER
+		byMethod = byMethod || g == GroupBy_GROUP_BY_METHOD
+	}
+
+	series := make(map[string]*alertSeries)
+	get := func(consumer, method string) *alertSeries {
+		labels := make(map[string]string)
+		if byConsumer || r.GetConsumer() != "" {
+			labels["consumer"] = consumer
+		}
+		if byMethod || r.GetMethod() != "" {
+			labels["method"] = method
+		}
+		key := labels["consumer"] + "\x00" + labels["method"]
+		s, ok := series[key]
+		if !ok {
+			s = &alertSeries{labels: labels}
+			series[key] = s
+		}
+		return s
+	}
+	match := func(consumer, method string) bool {
+		return (r.GetConsumer() == "" || r.GetConsumer() == consumer) &&
+			(r.GetMethod() == "" || r.GetMethod() == method)
+	}
+	if len(r.GetGroupBy()) == 0 {
+		get(r.GetConsumer(), r.GetMethod())
+	}
+
+	switch r.GetMetric() {
+	case AlertMetric_ALERT_METRIC_CALLS:
+		for consumer, methods := range acc.byConsumerMethod {
+			for method, n := range methods {
+				if match(consumer, method) {
+					get(consumer, method).num += n
+				}
+			}
+		}
+	case AlertMetric_ALERT_METRIC_ERROR_RATIO:
+		for _, gc := range acc.groups {
+			consumer, method, code := gc.values[0], gc.values[1], gc.values[2]
+			if !match(consumer, method) {
+				continue
+			}
+			s := get(consumer, method)
+			s.den += gc.count
+			if parseCode(code) != codes.OK {
+				s.num += gc.count
+			}
+		}
+	case AlertMetric_ALERT_METRIC_LATENCY_P99:
+		for method, sk := range acc.latencyByMethod {
+			if !match("", method) {
+				continue
+			}
+			s := get("", method)
+			if s.sketch == nil {
+				s.sketch = newLatencySketch()
+			}
+			s.sketch.merge(sk)
+		}
+	}
+	return series
+}
+
+func (s *alertSeries) value(m AlertMetric) float64 {
+	switch m {
+	case AlertMetric_ALERT_METRIC_ERROR_RATIO:
+		if s.den == 0 {
+			return 0
+		}
+		return float64(s.num) / float64(s.den)
+	case AlertMetric_ALERT_METRIC_LATENCY_P99:
+		if s.sketch == nil || s.sketch.count == 0 {
+			return 0
+		}
+		return s.sketch.quantile(0.99)
+	}
+	return float64(s.num)
+}
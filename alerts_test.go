@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// значение между порогом гашения и порогом срабатывания не меняет состояние
+func TestAlertHysteresis(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	path := filepath.Join(t.TempDir(), "rules.json")
+	am, err := NewAlertManager(ss, path)
+	if err != nil {
+		t.Fatalf("cannot create alert manager: %v", err)
+	}
+	err = am.SetRule(&AlertRule{
+		Name:          "errors",
+		Metric:        AlertMetric_ALERT_METRIC_ERROR_RATIO,
+		GroupBy:       []GroupBy{GroupBy_GROUP_BY_METHOD},
+		Threshold:     0.5,
+		WindowSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("cannot set rule: %v", err)
+	}
+	ch, _ := am.Subscribe()
+	defer am.Unsubscribe(ch)
+
+	call := func(code codes.Code) {
+		e := &Event{Consumer: "biz_user", Method: "/main.Biz/Add"}
+		ss.Record(e)
+		ss.Complete(e, code, time.Millisecond)
+	}
+	expect := func(state AlertState) {
+		t.Helper()
+		am.evaluate(time.Now())
+		select {
+		case a := <-ch:
+			if a.State != state || a.Labels["method"] != "/main.Biz/Add" {
+				t.Fatalf("expected %v, got %v", state, a)
+			}
+		default:
+			t.Fatalf("expected %v, got nothing", state)
+		}
+	}
+	expectNothing := func() {
+		t.Helper()
+		am.evaluate(time.Now())
+		select {
+		case a := <-ch:
+			t.Fatalf("unexpected alert %v", a)
+		default:
+		}
+	}
+
+	call(codes.OK)
+	call(codes.Internal)
+	call(codes.Internal)
+	expect(AlertState_ALERT_STATE_FIRING) // 2/3
+	expectNothing()
+
+	call(codes.OK)
+	expectNothing() // 2/4 - ниже порога, но выше порога гашения 0.45
+
+	call(codes.OK)
+	expect(AlertState_ALERT_STATE_RESOLVED) // 2/5
+
+	// правила переживают перезапуск
+	am, err = NewAlertManager(ss, path)
+	if err != nil {
+		t.Fatalf("cannot reload rules: %v", err)
+	}
+	if rules := am.Rules().GetRules(); len(rules) != 1 || rules[0].Threshold != 0.5 {
+		t.Fatalf("bad reloaded rules: %v", rules)
+	}
+}
+
+func TestAlertRuleValidation(t *testing.T) {
+	am, _ := NewAlertManager(NewSimpleEventStats(NewSequencer(), DefaultStatsConfig()), "")
+	bad := []*AlertRule{
+		{WindowSeconds: 60},
+		{Name: "no window"},
+		{Name: "too long", WindowSeconds: 3600},
+		{Name: "latency by consumer", Metric: AlertMetric_ALERT_METRIC_LATENCY_P99, Consumer: "biz_user", WindowSeconds: 60},
+		{Name: "resolve above threshold", Threshold: 1, ResolveThreshold: 2, WindowSeconds: 60},
+	}
+	for _, r := range bad {
+		if err := am.SetRule(r); err == nil {
+			t.Errorf("rule %v must be rejected", r)
+		}
+	}
+	if err := am.DeleteRule("missing"); err == nil {
+		t.Errorf("deleting missing rule must fail")
+	}
+}
+
+// вызовы, свёрнутые в "other" сверх MaxGroups, сохраняют код и не считаются ошибками
+func TestAlertErrorRatioOverflow(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.MaxGroups = 2
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+	am, _ := NewAlertManager(ss, "")
+	err := am.SetRule(&AlertRule{
+		Name:          "errors",
+		Metric:        AlertMetric_ALERT_METRIC_ERROR_RATIO,
+		Threshold:     0.1,
+		WindowSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("cannot set rule: %v", err)
+	}
+	ch, _ := am.Subscribe()
+	defer am.Unsubscribe(ch)
+
+	for i := 0; i < 10; i++ {
+		e := &Event{Consumer: fmt.Sprintf("consumer%d", i), Method: "/main.Biz/Add"}
+		ss.Record(e)
+		ss.Complete(e, codes.OK, time.Millisecond)
+	}
+	am.evaluate(time.Now())
+	select {
+	case a := <-ch:
+		t.Fatalf("only OK calls, unexpected alert %v", a)
+	default:
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "time"
+
+// Clock источник времени для событий, окон статистики и запросов Admin.
+// Таймеры потоков Statistics и Alerts и длительность вызовов всё равно
+// считаются по реальному времени, иначе с фиксированными часами задержки были бы нулевыми
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock настоящее время
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/keepalive"
+	"gopkg.in/yaml.v3"
+)
+
+// префикс переменных окружения: listen.addr -> HW7_LISTEN_ADDR
+const envPrefix = "HW7"
+
+// Config настройки serve. Слои применяются по порядку: значения по умолчанию,
+// YAML-файл, переменные окружения, флаги командной строки
+type Config struct {
+	Listen          ListenConfig    `yaml:"listen"`
+	ACL             ACLConfig       `yaml:"acl"`
+	TLS             TLSConfig       `yaml:"tls"`
+	Streams         StreamsConfig   `yaml:"streams"`
+	Logger          LoggerConfig    `yaml:"logger"`
+	Stats           StatsSettings   `yaml:"stats"`
+	Health          HealthConfig    `yaml:"health"`
+	Limits          LimitsConfig    `yaml:"limits"`
+	Keepalive       KeepaliveConfig `yaml:"keepalive"`
+	AlertRules      string          `yaml:"alert_rules"` // файл для WithAlertRules
+	Notifier        string          `yaml:"notifier"`    // файл для WithNotifier
+	ShutdownTimeout Duration        `yaml:"shutdown_timeout"`
+}
+
+// ListenConfig адреса host:port, [::1]:port, unix:path или unix:///abs/path
+type ListenConfig struct {
+	Addr        string   `yaml:"addr"`
+	Extra       []string `yaml:"extra"`        // в окружении через запятую
+	SocketMode  string   `yaml:"socket_mode"`  // права unix-сокетов, восьмеричное число
+	MetricsAddr string   `yaml:"metrics_addr"` // пусто - без /metrics
+}
+
+// ACLConfig ровно одно из file и inline
+type ACLConfig struct {
+	File   string `yaml:"file"`
+	Inline string `yaml:"inline"`
+}
+
+// TLSConfig сертификат и ключ файлами или PEM, например из переменных окружения
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	CertPEM      string `yaml:"cert_pem"`
+	KeyPEM       string `yaml:"key_pem" secret:"true"`
+	ClientCAFile string `yaml:"client_ca_file"` // задан - клиенты обязаны предъявить сертификат
+}
+
+// StreamsConfig пределы потоков Statistics
+type StreamsConfig struct {
+	MinInterval Duration `yaml:"min_interval"`
+	MaxInterval Duration `yaml:"max_interval"`
+	MaxWindow   Duration `yaml:"max_window"` // и глубина посекундного кольца
+}
+
+type LoggerConfig struct {
+	SubscriberBuffer int `yaml:"subscriber_buffer"`
+}
+
+type StatsSettings struct {
+	MaxConsumers int    `yaml:"max_consumers"`
+	MaxGroups    int    `yaml:"max_groups"`
+	TopK         int    `yaml:"top_k"`
+	TopKCapacity int    `yaml:"top_k_capacity"`
+	HistoryPath  string `yaml:"history_path"`
+	SLOs         string `yaml:"slos"` // файл для WithSLOs
+
+	HistorySaveInterval Duration `yaml:"history_save_interval"`
+	HistoryMaxConsumers int      `yaml:"history_max_consumers"`
+}
+
+type HealthConfig struct {
+	RequireACL bool `yaml:"require_acl"` // см. WithHealthCheckACL
+}
+
+// LimitsConfig 0 - без своего предела, см. WithMaxConnections, WithMaxStreams, WithMaxMessageSize
+type LimitsConfig struct {
+	MaxConnections int `yaml:"max_connections"`
+	MaxStreams     int `yaml:"max_streams"` // на одно соединение
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size"`
+	MaxSendMsgSize int `yaml:"max_send_msg_size"`
+}
+
+// KeepaliveConfig пинги сервера и политика для пингов клиентов, 0 у max_* - без предела
+type KeepaliveConfig struct {
+	Time                Duration `yaml:"time"`              // пинг после стольких секунд тишины
+	Timeout             Duration `yaml:"timeout"`           // не ответил на пинг - соединение закрывается
+	MinPingInterval     Duration `yaml:"min_ping_interval"` // клиент, который пингует чаще, получает GOAWAY
+	PermitWithoutStream bool     `yaml:"permit_without_stream"`
+	MaxIdle             Duration `yaml:"max_idle"`
+	MaxAge              Duration `yaml:"max_age"`
+	MaxAgeGrace         Duration `yaml:"max_age_grace"` // сколько ждать вызовы после max_age
+}
+
+// Duration time.Duration, который в YAML и окружении пишется как "30s"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %v", node.Line, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func DefaultConfig() *Config {
+	stats := DefaultStatsConfig()
+	return &Config{
+		Listen: ListenConfig{Addr: "127.0.0.1:8082", SocketMode: fmt.Sprintf("%04o", defaultSocketMode)},
+		Streams: StreamsConfig{
+			MinInterval: Duration(stats.MinInterval),
+			MaxInterval: Duration(stats.MaxInterval),
+			MaxWindow:   Duration(stats.MaxWindow),
+		},
+		Logger: LoggerConfig{SubscriberBuffer: subscriberBuffer},
+		Stats: StatsSettings{
+			MaxConsumers: stats.MaxConsumers,
+			MaxGroups:    stats.MaxGroups,
+			TopK:         stats.TopK,
+			TopKCapacity: stats.TopKCapacity,
+
+			HistorySaveInterval: Duration(stats.HistorySaveInterval),
+			HistoryMaxConsumers: stats.HistoryMaxConsumers,
+		},
+		Keepalive: KeepaliveConfig{
+			Time:                Duration(defaultKeepalive.Time),
+			Timeout:             Duration(defaultKeepalive.Timeout),
+			MinPingInterval:     Duration(defaultKeepalivePolicy.MinTime),
+			PermitWithoutStream: defaultKeepalivePolicy.PermitWithoutStream,
+		},
+		ShutdownTimeout: Duration(defaultShutdownTimeout),
+	}
+}
+
+// LoadConfig значения по умолчанию, поверх них файл path, если задан, и окружение.
+// Неизвестные ключи файла считаются ошибкой, чтобы опечатка не проходила молча
+func LoadConfig(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := DefaultConfig()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), envPrefix, lookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv переопределяет каждое поле переменной PREFIX_SECTION_KEY, если она задана
+func applyEnv(v reflect.Value, prefix string, lookupEnv func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		key := prefix + "_" + strings.ToUpper(name)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, key, lookupEnv); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := lookupEnv(key)
+		if !ok {
+			continue
+		}
+		var err error
+		switch p := field.Addr().Interface().(type) {
+		case *string:
+			*p = value
+		case *[]string:
+			// пустые элементы, в том числе от пустой переменной, отбрасываются
+			*p = nil
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*p = append(*p, item)
+				}
+			}
+		case *int:
+			*p, err = strconv.Atoi(value)
+		case *bool:
+			*p, err = strconv.ParseBool(value)
+		case *Duration:
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			*p = Duration(d)
+		default:
+			err = fmt.Errorf("unsupported type %v", field.Type())
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// Validate проверяет итоговую конфигурацию целиком и возвращает все ошибки сразу
+func (cfg *Config) Validate() error {
+	var errs []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+	check(validListenAddr(cfg.Listen.Addr), "listen.addr: bad address %q, want host:port, unix:path or unix:///abs/path", cfg.Listen.Addr)
+	_, err := cfg.socketMode()
+	check(err == nil, "listen.socket_mode: %v", err)
+	for _, addr := range cfg.Listen.Extra {
+		check(validListenAddr(addr), "listen.extra: bad address %q, want host:port, unix:path or unix:///abs/path", addr)
+	}
+	check((cfg.ACL.File == "") != (cfg.ACL.Inline == ""), "exactly one of acl.file and acl.inline is required")
+	if cfg.ACL.Inline != "" {
+		_, err := ParseACL(cfg.ACL.Inline)
+		check(err == nil, "acl.inline: %v", err)
+	}
+
+	hasCert := cfg.TLS.CertFile != "" || cfg.TLS.CertPEM != ""
+	hasKey := cfg.TLS.KeyFile != "" || cfg.TLS.KeyPEM != ""
+	check(hasCert == hasKey, "tls needs both certificate and key")
+	check(cfg.TLS.CertFile == "" || cfg.TLS.CertPEM == "", "only one of tls.cert_file and tls.cert_pem may be set")
+	check(cfg.TLS.KeyFile == "" || cfg.TLS.KeyPEM == "", "only one of tls.key_file and tls.key_pem may be set")
+	check(cfg.TLS.ClientCAFile == "" || hasCert, "tls.client_ca_file requires a server certificate")
+
+	check(cfg.Streams.MinInterval > 0, "streams.min_interval must be positive")
+	check(cfg.Streams.MaxInterval >= cfg.Streams.MinInterval, "streams.max_interval must not be less than min_interval")
+	check(cfg.Streams.MaxWindow >= Duration(time.Second), "streams.max_window must be at least 1s")
+	check(cfg.Logger.SubscriberBuffer > 0, "logger.subscriber_buffer must be positive")
+	check(cfg.Stats.MaxConsumers > 0, "stats.max_consumers must be positive")
+	check(cfg.Stats.MaxGroups > 0, "stats.max_groups must be positive")
+	check(cfg.Stats.TopK > 0 && cfg.Stats.TopK <= cfg.Stats.TopKCapacity, "stats.top_k must be in [1, top_k_capacity]")
+	check(cfg.Stats.HistorySaveInterval > 0, "stats.history_save_interval must be positive")
+	check(cfg.Stats.HistoryMaxConsumers > 0, "stats.history_max_consumers must be positive")
+	check(cfg.ShutdownTimeout > 0, "shutdown_timeout must be positive")
+
+	check(cfg.Limits.MaxConnections >= 0 && cfg.Limits.MaxStreams >= 0 &&
+		cfg.Limits.MaxRecvMsgSize >= 0 && cfg.Limits.MaxSendMsgSize >= 0, "limits must not be negative")
+	check(cfg.Keepalive.Time > 0 && cfg.Keepalive.Timeout > 0, "keepalive.time and keepalive.timeout must be positive")
+	check(cfg.Keepalive.MinPingInterval >= 0 && cfg.Keepalive.MaxIdle >= 0 &&
+		cfg.Keepalive.MaxAge >= 0 && cfg.Keepalive.MaxAgeGrace >= 0, "keepalive durations must not be negative")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+func (cfg *Config) socketMode() (os.FileMode, error) {
+	mode, err := strconv.ParseUint(cfg.Listen.SocketMode, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("bad permissions %q, want octal like 0660", cfg.Listen.SocketMode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// ACLData текст ACL из файла или из конфигурации
+func (cfg *Config) ACLData() (string, error) {
+	if cfg.ACL.Inline != "" {
+		return cfg.ACL.Inline, nil
+	}
+	data, err := os.ReadFile(cfg.ACL.File)
+	return string(data), err
+}
+
+// Options те же опции, что принимает StartMyMicroservice
+func (cfg *Config) Options() ([]Option, error) {
+	stats := DefaultStatsConfig()
+	stats.MaxConsumers = cfg.Stats.MaxConsumers
+	stats.MaxGroups = cfg.Stats.MaxGroups
+	stats.TopK = cfg.Stats.TopK
+	stats.TopKCapacity = cfg.Stats.TopKCapacity
+	stats.HistoryPath = cfg.Stats.HistoryPath
+	stats.HistorySaveInterval = time.Duration(cfg.Stats.HistorySaveInterval)
+	stats.HistoryMaxConsumers = cfg.Stats.HistoryMaxConsumers
+	stats.MinInterval = time.Duration(cfg.Streams.MinInterval)
+	stats.MaxInterval = time.Duration(cfg.Streams.MaxInterval)
+	stats.MaxWindow = time.Duration(cfg.Streams.MaxWindow)
+
+	opts := []Option{
+		WithStatsConfig(stats),
+		WithLoggerBuffer(cfg.Logger.SubscriberBuffer),
+		WithShutdownTimeout(time.Duration(cfg.ShutdownTimeout)),
+		WithHealthCheckACL(cfg.Health.RequireACL),
+		WithListen(cfg.Listen.Extra...),
+		WithMaxConnections(cfg.Limits.MaxConnections),
+		WithMaxStreams(uint32(cfg.Limits.MaxStreams)),
+		WithMaxMessageSize(cfg.Limits.MaxRecvMsgSize, cfg.Limits.MaxSendMsgSize),
+		WithKeepalive(keepalive.ServerParameters{
+			Time:                  time.Duration(cfg.Keepalive.Time),
+			Timeout:               time.Duration(cfg.Keepalive.Timeout),
+			MaxConnectionIdle:     time.Duration(cfg.Keepalive.MaxIdle),
+			MaxConnectionAge:      time.Duration(cfg.Keepalive.MaxAge),
+			MaxConnectionAgeGrace: time.Duration(cfg.Keepalive.MaxAgeGrace),
+		}, keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(cfg.Keepalive.MinPingInterval),
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
+	}
+	mode, err := cfg.socketMode()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithSocketMode(mode))
+	if cfg.Listen.MetricsAddr != "" {
+		opts = append(opts, WithMetricsAddr(cfg.Listen.MetricsAddr))
+	}
+	if cfg.AlertRules != "" {
+		opts = append(opts, WithAlertRules(cfg.AlertRules))
+	}
+	if cfg.Notifier != "" {
+		opts = append(opts, WithNotifier(cfg.Notifier))
+	}
+	if cfg.Stats.SLOs != "" {
+		opts = append(opts, WithSLOs(cfg.Stats.SLOs))
+	}
+	if cfg.TLS.CertFile != "" || cfg.TLS.CertPEM != "" {
+		tlsConfig, err := cfg.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTLS(tlsConfig))
+	}
+	return opts, nil
+}
+
+func (t TLSConfig) load() (*tls.Config, error) {
+	certPEM, keyPEM := []byte(t.CertPEM), []byte(t.KeyPEM)
+	var err error
+	if t.CertFile != "" {
+		certPEM, err = os.ReadFile(t.CertFile)
+	}
+	if err == nil && t.KeyFile != "" {
+		keyPEM, err = os.ReadFile(t.KeyFile)
+	}
+	var cert tls.Certificate
+	if err == nil {
+		cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tls: %v", err)
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if t.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(t.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("tls: no certificates in %s", t.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// Redacted YAML итоговой конфигурации, значения полей с тегом secret заменены
+func (cfg *Config) Redacted() ([]byte, error) {
+	c := *cfg
+	redact(reflect.ValueOf(&c).Elem())
+	return yaml.Marshal(&c)
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			redact(field)
+		} else if t.Field(i).Tag.Get("secret") == "true" && field.String() != "" {
+			field.SetString("<redacted>")
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// окружение важнее файла, секреты не печатаются
+func TestConfigLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`
+listen:
+  addr: 127.0.0.1:9000
+acl:
+  inline: '{"biz_user": ["/main.Biz/Check"]}'
+streams:
+  max_interval: 10m
+stats:
+  top_k: 5
+`), 0o644)
+	env := map[string]string{
+		"HW7_LISTEN_ADDR":                     "127.0.0.1:9001",
+		"HW7_STREAMS_MAX_WINDOW":              "5m",
+		"HW7_TLS_KEY_PEM":                     "very secret",
+		"HW7_LISTEN_EXTRA":                    "unix:/run/hw7.sock,[::1]:8082",
+		"HW7_KEEPALIVE_PERMIT_WITHOUT_STREAM": "false",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg, err := LoadConfig(path, lookup)
+	if err != nil {
+		t.Fatalf("cannot load config: %v", err)
+	}
+	if cfg.Listen.Addr != "127.0.0.1:9001" || cfg.Stats.TopK != 5 ||
+		cfg.Streams.MaxInterval != Duration(10*time.Minute) || cfg.Streams.MaxWindow != Duration(5*time.Minute) ||
+		cfg.Logger.SubscriberBuffer != subscriberBuffer || len(cfg.Listen.Extra) != 2 || cfg.Keepalive.PermitWithoutStream {
+		t.Fatalf("bad merged config: %+v", cfg)
+	}
+
+	// ключ без сертификата
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "both certificate and key") {
+		t.Fatalf("expected tls error, got %v", err)
+	}
+	out, err := cfg.Redacted()
+	if err != nil {
+		t.Fatalf("cannot print config: %v", err)
+	}
+	if strings.Contains(string(out), "very secret") || !strings.Contains(string(out), "<redacted>") {
+		t.Fatalf("secret is not redacted:\n%s", out)
+	}
+	if cfg.TLS.KeyPEM != "very secret" {
+		t.Fatalf("Redacted must not change the config")
+	}
+
+	// пустая переменная и лишние запятые не дают пустых адресов
+	env["HW7_LISTEN_EXTRA"] = ""
+	if cfg, err = LoadConfig(path, lookup); err != nil || len(cfg.Listen.Extra) != 0 {
+		t.Fatalf("empty list expected, got %q, %v", cfg.Listen.Extra, err)
+	}
+	env["HW7_LISTEN_EXTRA"] = " [::1]:8082, ,"
+	if cfg, err = LoadConfig(path, lookup); err != nil || len(cfg.Listen.Extra) != 1 || cfg.Listen.Extra[0] != "[::1]:8082" {
+		t.Fatalf("bad list: %q, %v", cfg.Listen.Extra, err)
+	}
+	cfg.TLS.KeyPEM = ""
+	cfg.Listen.Extra = []string{"127.0.0.1", "unix:"}
+	err = cfg.Validate()
+	if err == nil || strings.Count(err.Error(), "listen.extra") != 2 {
+		t.Fatalf("expected listen.extra errors, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("listen:\n  adr: 127.0.0.1:9000\n"), 0o644)
+	if _, err := LoadConfig(path, lookup); err == nil {
+		t.Fatalf("unknown key must be rejected")
+	}
+}
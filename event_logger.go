@@ -2,41 +2,87 @@ package main
 
 import (
 	"sync"
-	"time"
+)
+
+// сколько событий может накопиться у медленного подписчика, прежде чем
+// новые начнут отбрасываться
+const subscriberBuffer = 256
+
+// подписчики по назначению, для метрик: потоки Logging и Notifier
+const (
+	streamLogging  = "logging"
+	streamNotifier = "notifier"
 )
 
 type EventLogger interface {
-	LogEvent(consumer, method, host string)
+	LogEvent(consumer, method, host, listener string, kind EventKind) *Event
 	Subscribe() chan *Event
 	Unsubscribe(chan *Event)
 }
 
+// streamSubscriber логгер, который считает подписчиков и потери по назначению;
+// Subscribe у него подписывает поток Logging
+type streamSubscriber interface {
+	SubscribeStream(stream string) chan *Event
+}
+
 type SimpleEventLogger struct {
 	mu          sync.Mutex
-	subscribers map[chan *Event]struct{}
+	seq         *Sequencer
+	clock       Clock
+	subscribers map[chan *Event]string
+	dropped     map[string]uint64
+	buffer      int // ёмкость канала подписчика
 }
 
-func (el *SimpleEventLogger) LogEvent(consumer, method, host string) {
+func NewSimpleEventLogger(seq *Sequencer, clock Clock) *SimpleEventLogger {
+	return &SimpleEventLogger{
+		seq:         seq,
+		clock:       clock,
+		subscribers: make(map[chan *Event]string),
+		dropped:     make(map[string]uint64),
+		buffer:      subscriberBuffer,
+	}
+}
+
+func (el *SimpleEventLogger) LogEvent(consumer, method, host, listener string, kind EventKind) *Event {
 	e := &Event{
-		Timestamp: time.Now().Unix(),
-		Consumer:  consumer,
-		Method:    method,
-		Host:      host,
+		Consumer: consumer,
+		Method:   method,
+		Host:     host,
+		Listener: listener,
+		Kind:     kind,
 	}
 	el.mu.Lock()
 	defer el.mu.Unlock()
-	for sub := range el.subscribers {
-		sub <- e
+	// номер выдаётся под блокировкой, чтобы порядок доставки подписчикам совпадал с seq
+	el.seq.StampEvent(e, el.clock.Now())
+	for sub, stream := range el.subscribers {
+		// медленный подписчик не должен тормозить вызовы
+		select {
+		case sub <- e:
+		default:
+			el.dropped[stream]++
+		}
 	}
+	return e
 }
 
 func (el *SimpleEventLogger) Subscribe() chan *Event {
-	ch := make(chan *Event)
+	return el.SubscribeStream(streamLogging)
+}
+
+// SubscribeStream подписка, потери которой считаются отдельно под именем stream
+func (el *SimpleEventLogger) SubscribeStream(stream string) chan *Event {
+	ch := make(chan *Event, el.buffer)
 	el.mu.Lock()
 
 	defer el.mu.Unlock()
 
-	el.subscribers[ch] = struct{}{}
+	el.subscribers[ch] = stream
+	if _, ok := el.dropped[stream]; !ok {
+		el.dropped[stream] = 0
+	}
 
 	return ch
 }
@@ -46,3 +92,27 @@ func (el *SimpleEventLogger) Unsubscribe(ch chan *Event) {
 	defer el.mu.Unlock()
 	delete(el.subscribers, ch)
 }
+
+// Subscribers число активных подписчиков Logging
+func (el *SimpleEventLogger) Subscribers() int {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	n := 0
+	for _, stream := range el.subscribers {
+		if stream == streamLogging {
+			n++
+		}
+	}
+	return n
+}
+
+// Dropped сколько событий не было доставлено переполненным подписчикам, по назначению
+func (el *SimpleEventLogger) Dropped() map[string]uint64 {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	res := make(map[string]uint64, len(el.dropped))
+	for stream, n := range el.dropped {
+		res[stream] = n
+	}
+	return res
+}
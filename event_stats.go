@@ -1,48 +1,530 @@
 package main
 
 import (
+	"log"
+	"os"
 	"sync"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type EventStats interface {
-	UpdateStat(stat *Stat, e *Event)
-	Subscribe() chan *Event
-	Unsubscribe(chan *Event)
+	Record(e *Event)
+	Complete(e *Event, code codes.Code, latency time.Duration)
+	Reject(e *Event, code codes.Code)
+	Subscribe(req *StatInterval) (*StatWindow, error)
+	Unsubscribe(*StatWindow)
+	Flush(w *StatWindow, now time.Time) *Stat
+	Query(q *StatQuery, now time.Time) (*Stat, error)
+	TopK(q *TopKQuery, now time.Time) (*TopKReply, error)
+	History(q *HistoryQuery, now time.Time) (*HistoryReply, error)
+	SLOs(q *SLOQuery, now time.Time) (*SLOReport, error)
+	Close() error
+}
+
+// StatWindow окно агрегации одного подписчика Statistics.
+// Окна независимы: у каждого свой аккумулятор, который сбрасывается только его владельцем
+type StatWindow struct {
+	spec  windowSpec
+	acc   *statAcc // только для STAT_MODE_DELTA
+	start time.Time
+}
+
+// NewStatWindow окно для собственной реализации EventStats: Statistics
+// берёт из него только расписание отправки, остальное остаётся реализации.
+// Связать окно со своим состоянием можно, используя *StatWindow как ключ
+func NewStatWindow(interval time.Duration, align bool) *StatWindow {
+	return &StatWindow{spec: windowSpec{interval: interval, align: align}}
+}
+
+// Interval как часто Statistics отправляет окно
+func (w *StatWindow) Interval() time.Duration {
+	return w.spec.interval
 }
 
+// StatsConfig ограничения подсистемы статистики
+type StatsConfig struct {
+	MaxConsumers int // потребителей в разрезе consumer x method, остальные попадают в "other"
+	MaxGroups    int // верхняя граница для StatInterval.max_groups
+	// сколько секунд истории хранится посекундно, это же предел для STAT_MODE_SLIDING
+	MaxWindow time.Duration
+	// допустимые интервалы потока Statistics
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// сколько самых частых ключей отдавать и сколько счётчиков держать под них;
+	// погрешность top-K не больше числа вызовов / TopKCapacity
+	TopK         int
+	TopKCapacity int
+	// файл, в который сохраняются свёртки истории раз в HistorySaveInterval и при остановке;
+	// корзины SLO сохраняются рядом, в HistoryPath + ".slo". Пусто - не сохранять
+	HistoryPath         string
+	HistorySaveInterval time.Duration
+	// потребителей в одной корзине истории, остальные попадают в "other".
+	// Корзин 3600 + 1440 + 720, в каждой до HistoryMaxConsumers + 1 + число методов
+	// точек по сотне байт, так что при 100 история занимает до ~60 МиБ
+	HistoryMaxConsumers int
+	// цели по методам, см. LoadSLOs
+	SLOs []*SLO
+	// nil - SystemClock
+	Clock Clock
+}
+
+func DefaultStatsConfig() StatsConfig {
+	return StatsConfig{
+		MaxConsumers: 1000,
+		MaxGroups:    1000,
+		MaxWindow:    15 * time.Minute,
+		MinInterval:  100 * time.Millisecond,
+		MaxInterval:  time.Hour,
+		TopK:         10,
+		TopKCapacity: 100,
+
+		HistorySaveInterval: time.Minute,
+		HistoryMaxConsumers: 100,
+	}
+}
+
+// SimpleEventStats раскладывает каждое событие по всем открытым окнам
+// и по накопителю с момента старта сервера.
+// Запись и сброс окна идут под одной блокировкой, поэтому событие на границе
+// попадает ровно в одно из двух соседних окон
 type SimpleEventStats struct {
-	mu          sync.Mutex
-	subscribers map[chan *Event]struct{}
-	stats       Stat
+	mu      sync.Mutex
+	cfg     StatsConfig
+	seq     *Sequencer
+	windows map[*StatWindow]struct{}
+	started time.Time
+	total   *statAcc
+	ring    *timeRing
+	history *statHistory
+	load    *loadTracker
+	slo     *sloTracker
+
+	// копии истории и SLO для сохранения, их трогают только persist и Close, по очереди
+	saved     *statHistory
+	savedSLO  *sloTracker
+	stop      chan struct{}
+	persisted chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSimpleEventStats нулевые и отрицательные пределы cfg заменяются значениями
+// DefaultStatsConfig: с нулевой ёмкостью top-K, например, TopK делил бы на ноль
+func NewSimpleEventStats(seq *Sequencer, cfg StatsConfig) *SimpleEventStats {
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock{}
+	}
+	def := DefaultStatsConfig()
+	for _, f := range []struct{ v, d *int }{
+		{&cfg.MaxConsumers, &def.MaxConsumers},
+		{&cfg.MaxGroups, &def.MaxGroups},
+		{&cfg.TopK, &def.TopK},
+		{&cfg.TopKCapacity, &def.TopKCapacity},
+		{&cfg.HistoryMaxConsumers, &def.HistoryMaxConsumers},
+	} {
+		if *f.v <= 0 {
+			*f.v = *f.d
+		}
+	}
+	for _, f := range []struct{ v, d *time.Duration }{
+		{&cfg.MaxWindow, &def.MaxWindow},
+		{&cfg.MinInterval, &def.MinInterval},
+		{&cfg.MaxInterval, &def.MaxInterval},
+		{&cfg.HistorySaveInterval, &def.HistorySaveInterval},
+	} {
+		if *f.v <= 0 {
+			*f.v = *f.d
+		}
+	}
+	storeSpec := windowSpec{
+		maxConsumers: cfg.MaxConsumers,
+		groupBy:      storeGroupBy,
+		maxGroups:    cfg.MaxGroups,
+		topK:         cfg.TopK,
+		topCapacity:  cfg.TopKCapacity,
+	}
+	now := cfg.Clock.Now()
+	ss := &SimpleEventStats{
+		cfg:     cfg,
+		seq:     seq,
+		windows: make(map[*StatWindow]struct{}),
+		started: now,
+		load:    newLoadTracker(cfg.MaxConsumers, now),
+		total:   newStatAcc(storeSpec),
+		ring:    newTimeRing(storeSpec, cfg.MaxWindow),
+		history: newStatHistory(cfg.HistoryMaxConsumers),
+		slo:     newSLOTracker(cfg.SLOs, now),
+	}
+	if cfg.HistoryPath != "" {
+		err := ss.history.load(cfg.HistoryPath)
+		if err != nil && !os.IsNotExist(err) {
+			log.Println("Cannot load statistics history: ", err)
+		}
+		err = ss.slo.load(sloPath(cfg.HistoryPath))
+		if err != nil && !os.IsNotExist(err) {
+			log.Println("Cannot load SLO history: ", err)
+		}
+		ss.saved = newStatHistory(cfg.HistoryMaxConsumers)
+		ss.savedSLO = newSLOTracker(cfg.SLOs, now)
+		for i, s := range ss.savedSLO.series {
+			s.started = ss.slo.series[i].started
+		}
+		ss.stop = make(chan struct{})
+		ss.persisted = make(chan struct{})
+		go ss.persist()
+	}
+	return ss
+}
+
+// persist сохраняет историю каждые HistorySaveInterval, чтобы при падении процесса
+// терялся один интервал, а не всё с прошлой остановки
+func (ss *SimpleEventStats) persist() {
+	defer close(ss.persisted)
+	ticker := time.NewTicker(ss.cfg.HistorySaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ss.stop:
+			return
+		case <-ticker.C:
+			if err := ss.saveHistory(); err != nil {
+				log.Println("Cannot save statistics history: ", err)
+			}
+		}
+	}
+}
+
+// saveHistory под блокировкой копируются только корзины истории и SLO, изменённые
+// с прошлого сохранения; сериализация копий и запись файлов идут без неё
+func (ss *SimpleEventStats) saveHistory() error {
+	ss.mu.Lock()
+	changes := ss.history.changes()
+	sloChanges := ss.slo.changes()
+	ss.mu.Unlock()
+
+	ss.saved.apply(changes)
+	data, err := ss.saved.marshal()
+	if err != nil {
+		return err
+	}
+	if err := writeHistory(ss.cfg.HistoryPath, data); err != nil {
+		return err
+	}
+	ss.savedSLO.apply(sloChanges)
+	if data, err = ss.savedSLO.marshal(); err != nil {
+		return err
+	}
+	return writeHistory(sloPath(ss.cfg.HistoryPath), data)
+}
+
+func sloPath(historyPath string) string {
+	return historyPath + ".slo"
+}
+
+func (ss *SimpleEventStats) Record(e *Event) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	now := ss.now()
+	ss.total.add(e)
+	ss.ring.slot(now).add(e)
+	ss.history.add(e, now)
+	ss.load.add(e, now)
+	for w := range ss.windows {
+		if w.acc != nil {
+			w.acc.add(e)
+		}
+	}
+}
+
+// Complete учитывает завершение вызова, ранее переданного в Record
+func (ss *SimpleEventStats) Complete(e *Event, code codes.Code, latency time.Duration) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	now := ss.now()
+	ss.total.complete(e, code, latency)
+	ss.ring.slot(now).complete(e, code, latency)
+	ss.history.outcome(e, code, latency, true, now)
+	ss.slo.complete(e, code, latency, now)
+	for w := range ss.windows {
+		if w.acc != nil {
+			w.acc.complete(e, code, latency)
+		}
+	}
+}
+
+// Reject учитывает вызов, отклонённый до обработчика: время выполнения не пишется
+func (ss *SimpleEventStats) Reject(e *Event, code codes.Code) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	now := ss.now()
+	ss.total.outcome(e, code)
+	ss.ring.slot(now).outcome(e, code)
+	ss.history.outcome(e, code, 0, false, now)
+	for w := range ss.windows {
+		if w.acc != nil {
+			w.acc.outcome(e, code)
+		}
+	}
+}
+
+func (ss *SimpleEventStats) now() time.Time {
+	return ss.cfg.Clock.Now()
+}
+
+// Windows число открытых потоков Statistics
+func (ss *SimpleEventStats) Windows() int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return len(ss.windows)
+}
+
+// snapshot копия накопителя с момента старта
+func (ss *SimpleEventStats) snapshot() *statAcc {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	acc := newStatAcc(ss.total.spec)
+	acc.merge(ss.total)
+	return acc
+}
+
+// since копия кольца за последние d
+func (ss *SimpleEventStats) since(now time.Time, d time.Duration) *statAcc {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.ring.since(now, d)
 }
 
-func (ss *SimpleEventStats) InitStat() *Stat {
-	return &Stat{
-		ByMethod:   make(map[string]uint64),
-		ByConsumer: make(map[string]uint64),
+func (ss *SimpleEventStats) Subscribe(req *StatInterval) (*StatWindow, error) {
+	spec, err := ss.windowSpec(req)
+	if err != nil {
+		return nil, err
+	}
+	w := &StatWindow{spec: spec, start: ss.now()}
+	if spec.mode == StatMode_STAT_MODE_DELTA {
+		w.acc = newStatAcc(spec)
 	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.windows[w] = struct{}{}
+	return w, nil
 }
 
-func (ss *SimpleEventStats) UpdateStat(e *Event) {
-	ss.stats.Timestamp = time.Now().Unix()
-	ss.stats.ByConsumer[e.Consumer]++
-	ss.stats.ByMethod[e.Method]++
+func (ss *SimpleEventStats) Unsubscribe(w *StatWindow) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.windows, w)
 }
 
-func (ss *SimpleEventStats) Subscribe() chan *Event {
-	ch := make(chan *Event)
+// Flush возвращает статистику окна подписчика на момент now.
+// В режиме DELTA окно закрывается и сразу открывается следующее
+func (ss *SimpleEventStats) Flush(w *StatWindow, now time.Time) *Stat {
+	var acc *statAcc
+	start := w.start
+
 	ss.mu.Lock()
+	loadByMethod, loadByConsumer := ss.load.snapshot(now)
+	switch w.spec.mode {
+	case StatMode_STAT_MODE_CUMULATIVE:
+		acc = newStatAcc(ss.total.spec)
+		acc.merge(ss.total)
+		start = ss.started
+	case StatMode_STAT_MODE_SLIDING:
+		acc = ss.ring.since(now, w.spec.window)
+		start = now.Add(-w.spec.window)
+	default:
+		acc = w.acc
+		w.acc = newStatAcc(w.spec)
+		w.start = now
+	}
+	ss.mu.Unlock()
+
+	if w.spec.mode != StatMode_STAT_MODE_DELTA {
+		acc.regroup(w.spec)
+	}
+	stat := acc.toStat()
+	stat.LoadByMethod, stat.LoadByConsumer = loadByMethod, loadByConsumer
+	fillRates(stat, now.Sub(start))
+	stat.Mode = w.spec.mode
+	stat.WindowStart = timestamppb.New(start)
+	stat.WindowEnd = timestamppb.New(now)
+	ss.seq.StampStat(stat, now)
+	return stat
+}
+
+// Query снимок статистики за запрошенный промежуток без подписки на поток
+func (ss *SimpleEventStats) Query(q *StatQuery, now time.Time) (*Stat, error) {
+	acc, start, mode, err := ss.rangeAcc(q.GetRange(), now, stringSet(q.GetConsumers()), stringSet(q.GetMethods()))
+	if err != nil {
+		return nil, err
+	}
+
+	acc.regroup(windowSpec{
+		maxGroups: ss.cfg.MaxGroups,
+		topK:      ss.cfg.TopK,
+		sketches:  q.GetIncludeSketches(),
+	})
+	stat := acc.toStat()
+	ss.mu.Lock()
+	stat.LoadByMethod, stat.LoadByConsumer = ss.load.snapshot(now)
+	ss.mu.Unlock()
+	fillRates(stat, now.Sub(start))
+	stat.Mode = mode
+	stat.WindowStart = timestamppb.New(start)
+	stat.WindowEnd = timestamppb.New(now)
+	ss.seq.StampStat(stat, now)
+	return stat, nil
+}
+
+// TopK самые частые потребители, методы и пары за промежуток
+func (ss *SimpleEventStats) TopK(q *TopKQuery, now time.Time) (*TopKReply, error) {
+	acc, start, _, err := ss.rangeAcc(q.GetRange(), now, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &TopKReply{
+		Total:       acc.topConsumers.total,
+		MaxError:    acc.topConsumers.total / uint64(ss.cfg.TopKCapacity),
+		WindowStart: timestamppb.New(start),
+		WindowEnd:   timestamppb.New(now),
+	}
+	reply.Consumers, reply.Methods, reply.Pairs = acc.heavyHitters(ss.topK(q.GetK()))
+	return reply, nil
+}
+
+// History временной ряд по потребителю или методу из свёрток
+func (ss *SimpleEventStats) History(q *HistoryQuery, now time.Time) (*HistoryReply, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.history.query(q, now)
+}
 
+// SLOs соблюдение целей и скорость расхода бюджета ошибок
+func (ss *SimpleEventStats) SLOs(q *SLOQuery, now time.Time) (*SLOReport, error) {
+	ss.mu.Lock()
 	defer ss.mu.Unlock()
+	return ss.slo.report(q, now)
+}
 
-	ss.subscribers[ch] = struct{}{}
+// Close останавливает периодическое сохранение и сохраняет историю, если задан HistoryPath
+func (ss *SimpleEventStats) Close() error {
+	if ss.cfg.HistoryPath == "" {
+		return nil
+	}
+	ss.stopPersist()
+	return ss.saveHistory()
+}
 
-	return ch
+// stopPersist останавливает периодическое сохранение, ничего не сохраняя
+func (ss *SimpleEventStats) stopPersist() {
+	if ss.stop == nil {
+		return
+	}
+	ss.closeOnce.Do(func() { close(ss.stop) })
+	<-ss.persisted
 }
 
-func (ss *SimpleEventStats) Unsubscribe(ch chan *Event) {
+// rangeAcc копия накопителя хранилища за промежуток r с фильтрами по потребителям и методам
+func (ss *SimpleEventStats) rangeAcc(r StatRange, now time.Time, consumers, methods map[string]bool) (*statAcc, time.Time, StatMode, error) {
+	var window time.Duration
+	switch r {
+	case StatRange_STAT_RANGE_LAST_1M:
+		window = time.Minute
+	case StatRange_STAT_RANGE_LAST_5M:
+		window = 5 * time.Minute
+	case StatRange_STAT_RANGE_LAST_15M:
+		window = 15 * time.Minute
+	case StatRange_STAT_RANGE_SINCE_START:
+	default:
+		return nil, now, 0, status.Errorf(codes.InvalidArgument, "unknown range %v", r)
+	}
+	if window > ss.cfg.MaxWindow {
+		return nil, now, 0, status.Errorf(codes.InvalidArgument, "range %v exceeds retained history of %v", r, ss.cfg.MaxWindow)
+	}
+
 	ss.mu.Lock()
 	defer ss.mu.Unlock()
-	delete(ss.subscribers, ch)
+	if window == 0 {
+		return ss.total.filter(consumers, methods), ss.started, StatMode_STAT_MODE_CUMULATIVE, nil
+	}
+	return ss.ring.since(now, window).filter(consumers, methods), now.Add(-window), StatMode_STAT_MODE_SLIDING, nil
+}
+
+// topK запрошенное K, ограниченное числом счётчиков сводки
+func (ss *SimpleEventStats) topK(k uint32) int {
+	if k == 0 {
+		return ss.cfg.TopK
+	}
+	if int(k) > ss.cfg.TopKCapacity {
+		return ss.cfg.TopKCapacity
+	}
+	return int(k)
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// boundedDuration n единиц unit, если это не больше max. Сравнение идёт до умножения,
+// потому что огромное n переполнило бы Duration и прошло бы проверку
+func boundedDuration(n uint64, unit, max time.Duration) (time.Duration, bool) {
+	if n > uint64(max/unit) {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+func (ss *SimpleEventStats) windowSpec(req *StatInterval) (windowSpec, error) {
+	spec := windowSpec{
+		maxConsumers: ss.cfg.MaxConsumers,
+		maxGroups:    ss.cfg.MaxGroups,
+		mode:         req.GetMode(),
+		align:        req.GetAlign(),
+		topK:         ss.topK(req.GetTopK()),
+		topCapacity:  ss.cfg.TopKCapacity,
+		sketches:     req.GetIncludeSketches(),
+	}
+	n, unit := req.GetIntervalSeconds(), time.Second
+	if ms := req.GetIntervalMillis(); ms > 0 {
+		n, unit = ms, time.Millisecond
+	}
+	interval, ok := boundedDuration(n, unit, ss.cfg.MaxInterval)
+	spec.interval = interval
+	if !ok || spec.interval < ss.cfg.MinInterval {
+		return spec, status.Errorf(codes.InvalidArgument, "interval %d x %v is out of [%v, %v]", n, unit, ss.cfg.MinInterval, ss.cfg.MaxInterval)
+	}
+	if n := int(req.GetMaxGroups()); n > 0 && n < spec.maxGroups {
+		spec.maxGroups = n
+	}
+
+	seen := make(map[GroupBy]bool)
+	for _, g := range req.GetGroupBy() {
+		if g == GroupBy_GROUP_BY_UNSPECIFIED || seen[g] {
+			continue
+		}
+		if (g == GroupBy_GROUP_BY_PEER || g == GroupBy_GROUP_BY_LISTENER) && spec.mode != StatMode_STAT_MODE_DELTA {
+			return spec, status.Errorf(codes.InvalidArgument, "group by %s is only available in delta mode", groupLabel(g))
+		}
+		seen[g] = true
+		spec.groupBy = append(spec.groupBy, g)
+	}
+
+	switch spec.mode {
+	case StatMode_STAT_MODE_DELTA, StatMode_STAT_MODE_CUMULATIVE:
+	case StatMode_STAT_MODE_SLIDING:
+		spec.window, ok = boundedDuration(req.GetWindowSeconds(), time.Second, ss.cfg.MaxWindow)
+		if !ok || spec.window <= 0 {
+			return spec, status.Errorf(codes.InvalidArgument, "window_seconds must be in (0, %d]", int64(ss.cfg.MaxWindow/time.Second))
+		}
+	default:
+		return spec, status.Errorf(codes.InvalidArgument, "unknown mode %v", spec.mode)
+	}
+	return spec, nil
 }
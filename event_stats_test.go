@@ -0,0 +1,391 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// окна разных подписчиков не влияют друг на друга
+func TestStatWindowsIndependent(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	w1, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1})
+	w2, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1})
+	defer ss.Unsubscribe(w1)
+	defer ss.Unsubscribe(w2)
+
+	ss.Record(&Event{Consumer: "biz_user", Method: "/main.Biz/Check"})
+	st1 := ss.Flush(w1, time.Now())
+	ss.Record(&Event{Consumer: "biz_user", Method: "/main.Biz/Add"})
+	st2 := ss.Flush(w2, time.Now())
+
+	if st1.ByConsumer["biz_user"] != 1 || st1.ByMethod["/main.Biz/Add"] != 0 {
+		t.Fatalf("bad first window: %v", st1)
+	}
+	if st2.ByConsumer["biz_user"] != 2 || st2.ByMethod["/main.Biz/Add"] != 1 {
+		t.Fatalf("bad second window: %v", st2)
+	}
+	if st2.Seq <= st1.Seq {
+		t.Fatalf("seq is not monotonic: %d then %d", st1.Seq, st2.Seq)
+	}
+}
+
+// события, пришедшие во время сброса окна, не теряются
+func TestStatWindowBoundary(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	w, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1})
+	defer ss.Unsubscribe(w)
+
+	const writers, perWriter = 8, 1000
+	wg := &sync.WaitGroup{}
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				ss.Record(&Event{Consumer: "c", Method: "m"})
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var total uint64
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			total += ss.Flush(w, time.Now()).ByMethod["m"]
+		}
+	}()
+	wg.Wait()
+	<-done
+	total += ss.Flush(w, time.Now()).ByMethod["m"]
+
+	if total != writers*perWriter {
+		t.Fatalf("lost events on window boundary: have %d, want %d", total, writers*perWriter)
+	}
+}
+
+func TestStatCodes(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	w, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1})
+	defer ss.Unsubscribe(w)
+
+	ok := &Event{Consumer: "biz_user", Method: "/main.Biz/Check"}
+	denied := &Event{Consumer: "biz_user", Method: "/main.Biz/Test"}
+	failed := &Event{Consumer: "biz_admin", Method: "/main.Biz/Check"}
+	for _, e := range []*Event{ok, denied, failed} {
+		ss.Record(e)
+	}
+	ss.Complete(ok, codes.OK, time.Millisecond)
+	ss.Reject(denied, codes.Unauthenticated)
+	ss.Complete(failed, codes.Internal, time.Millisecond)
+
+	st := ss.Flush(w, time.Now())
+	if n := st.CodesByMethod["/main.Biz/Check"].ByCode["Internal"]; n != 1 {
+		t.Errorf("bad Internal count for Check: %d", n)
+	}
+	if n := st.CodesByConsumer["biz_user"].ByCode["Unauthenticated"]; n != 1 {
+		t.Errorf("bad Unauthenticated count for biz_user: %d", n)
+	}
+	if r := st.ErrorRatioByMethod["/main.Biz/Check"]; r != 0.5 {
+		t.Errorf("bad error ratio for Check: %v", r)
+	}
+	if l := st.LatencyByMethod["/main.Biz/Test"]; l != nil {
+		t.Errorf("rejected call must not have latency: %v", l)
+	}
+}
+
+func TestStatGroupsCardinality(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.MaxConsumers = 2
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+	w, _ := ss.Subscribe(&StatInterval{
+		IntervalSeconds: 1,
+		GroupBy:         []GroupBy{GroupBy_GROUP_BY_CONSUMER, GroupBy_GROUP_BY_METHOD},
+		MaxGroups:       2,
+	})
+	defer ss.Unsubscribe(w)
+
+	for _, consumer := range []string{"a", "b", "c", "d"} {
+		ss.Record(&Event{Consumer: consumer, Method: "/main.Biz/Add"})
+	}
+	st := ss.Flush(w, time.Now())
+
+	if n := st.ByConsumerMethod[otherBucket].ByMethod["/main.Biz/Add"]; n != 2 {
+		t.Errorf("bad other bucket in by_consumer_method: %d", n)
+	}
+	if len(st.Groups) != 3 {
+		t.Fatalf("want 2 groups and other, have %v", st.Groups)
+	}
+	if g := st.Groups[0]; g.Count != 2 || g.Labels["consumer"] != otherBucket || g.Labels["method"] != otherBucket {
+		t.Errorf("bad other group: %v", g)
+	}
+}
+
+func TestStatModes(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	delta, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1, Mode: StatMode_STAT_MODE_DELTA})
+	cumulative, _ := ss.Subscribe(&StatInterval{
+		IntervalSeconds: 1,
+		Mode:            StatMode_STAT_MODE_CUMULATIVE,
+		GroupBy:         []GroupBy{GroupBy_GROUP_BY_METHOD, GroupBy_GROUP_BY_CODE},
+	})
+	sliding, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1, Mode: StatMode_STAT_MODE_SLIDING, WindowSeconds: 60})
+
+	for i := 0; i < 2; i++ {
+		e := &Event{Consumer: "biz_user", Method: "/main.Biz/Add"}
+		ss.Record(e)
+		ss.Complete(e, codes.OK, time.Millisecond)
+		ss.Flush(delta, time.Now())
+	}
+
+	now := time.Now()
+	if n := ss.Flush(delta, now).ByMethod["/main.Biz/Add"]; n != 0 {
+		t.Errorf("delta must be empty after flush, have %d", n)
+	}
+	st := ss.Flush(cumulative, now)
+	if n := st.ByMethod["/main.Biz/Add"]; n != 2 {
+		t.Errorf("bad cumulative count: %d", n)
+	}
+	if len(st.Groups) != 1 || st.Groups[0].Count != 2 || st.Groups[0].Labels["code"] != "OK" {
+		t.Errorf("bad cumulative groups: %v", st.Groups)
+	}
+	if st.Mode != StatMode_STAT_MODE_CUMULATIVE || !st.WindowStart.AsTime().Before(st.WindowEnd.AsTime()) {
+		t.Errorf("bad cumulative window: %v", st)
+	}
+
+	if n := ss.Flush(sliding, now).ByMethod["/main.Biz/Add"]; n != 2 {
+		t.Errorf("bad sliding count: %d", n)
+	}
+	if n := ss.Flush(sliding, now.Add(2*time.Minute)).ByMethod["/main.Biz/Add"]; n != 0 {
+		t.Errorf("sliding window must forget old calls, have %d", n)
+	}
+
+	if _, err := ss.Subscribe(&StatInterval{IntervalSeconds: 1, Mode: StatMode_STAT_MODE_SLIDING}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for empty sliding window, have %v", err)
+	}
+}
+
+func TestStatQuery(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	for _, e := range []*Event{
+		{Consumer: "biz_user", Method: "/main.Biz/Add"},
+		{Consumer: "biz_user", Method: "/main.Biz/Check"},
+		{Consumer: "biz_admin", Method: "/main.Biz/Add"},
+	} {
+		ss.Record(e)
+		ss.Complete(e, codes.OK, time.Millisecond)
+	}
+
+	st, err := ss.Query(&StatQuery{
+		Range:     StatRange_STAT_RANGE_LAST_1M,
+		Consumers: []string{"biz_user"},
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.ByMethod["/main.Biz/Add"] != 1 || st.ByMethod["/main.Biz/Check"] != 1 || st.ByConsumer["biz_admin"] != 0 {
+		t.Errorf("bad filtered stat: %v", st)
+	}
+	if n := st.CodesByMethod["/main.Biz/Add"].ByCode["OK"]; n != 1 {
+		t.Errorf("bad filtered codes: %d", n)
+	}
+
+	st, _ = ss.Query(&StatQuery{
+		Range:   StatRange_STAT_RANGE_SINCE_START,
+		Methods: []string{"/main.Biz/Add"},
+	}, time.Now())
+	if st.ByConsumer["biz_user"] != 1 || st.ByConsumer["biz_admin"] != 1 || st.Mode != StatMode_STAT_MODE_CUMULATIVE {
+		t.Errorf("bad since-start stat: %v", st)
+	}
+}
+
+func TestStatHistory(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.HistoryPath = filepath.Join(t.TempDir(), "history.json")
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+
+	e := &Event{Consumer: "biz_user", Method: "/main.Biz/Add"}
+	ss.Record(e)
+	ss.Complete(e, codes.Internal, 10*time.Millisecond)
+	now := time.Now()
+
+	q := &HistoryQuery{Method: "/main.Biz/Add", From: timestamppb.New(now.Add(-10 * time.Second))}
+	reply, err := ss.History(q, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply.StepSeconds != 1 {
+		t.Errorf("expected per-second resolution, have %d", reply.StepSeconds)
+	}
+	last := reply.Points[len(reply.Points)-1]
+	if last.Calls != 1 || last.Errors != 1 || last.LatencyAvg < 0.009 {
+		t.Errorf("bad last point: %v", last)
+	}
+
+	reply, _ = ss.History(&HistoryQuery{Consumer: "biz_user", From: timestamppb.New(now.Add(-2 * time.Hour))}, now)
+	if reply.StepSeconds != 60 {
+		t.Errorf("expected per-minute resolution, have %d", reply.StepSeconds)
+	}
+
+	if err := ss.Close(); err != nil {
+		t.Fatalf("cant save history: %v", err)
+	}
+	restored := NewSimpleEventStats(NewSequencer(), cfg)
+	reply, _ = restored.History(q, now)
+	if last := reply.Points[len(reply.Points)-1]; last.Calls != 1 {
+		t.Errorf("history was not restored: %v", last)
+	}
+
+	if _, err := ss.History(&HistoryQuery{From: q.From}, now); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without consumer and method, have %v", err)
+	}
+}
+
+// к сохранению копируются только корзины, изменённые с прошлого раза
+func TestStatHistoryChanges(t *testing.T) {
+	h := newStatHistory(10)
+	h.add(&Event{Consumer: "biz_user", Method: "/main.Biz/Add"}, time.Now())
+	if changes := h.changes(); len(changes) != len(historyTiers) {
+		t.Fatalf("expected one changed bucket per tier, got %d", len(changes))
+	}
+	if changes := h.changes(); len(changes) != 0 {
+		t.Fatalf("nothing changed since the last save, got %d", len(changes))
+	}
+}
+
+// история сохраняется и без остановки, падение процесса теряет только последний интервал
+func TestStatHistoryPeriodicSave(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.HistoryPath = filepath.Join(t.TempDir(), "history.json")
+	cfg.HistorySaveInterval = 10 * time.Millisecond
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+	defer ss.Close()
+	ss.Record(&Event{Consumer: "biz_user", Method: "/main.Biz/Add"})
+
+	now := time.Now()
+	q := &HistoryQuery{Method: "/main.Biz/Add", From: timestamppb.New(now.Add(-10 * time.Second))}
+	for deadline := now.Add(3 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		saved := newStatHistory(cfg.HistoryMaxConsumers)
+		saved.load(cfg.HistoryPath)
+		reply, _ := saved.query(q, now)
+		if reply.Points[len(reply.Points)-1].Calls == 1 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("history was not saved before Close")
+		}
+	}
+}
+
+func TestLoadAverage(t *testing.T) {
+	start := time.Now()
+	lt := newLoadTracker(10, start)
+	e := &Event{Consumer: "biz_user", Method: "/main.Biz/Add"}
+
+	// 10 вызовов в секунду в течение часа
+	now := start
+	for i := 0; i < 3600*10; i++ {
+		now = start.Add(time.Duration(i) * 100 * time.Millisecond)
+		lt.add(e, now)
+	}
+	byMethod, byConsumer := lt.snapshot(now.Add(loadTick))
+	for _, la := range []*LoadAverage{byMethod["/main.Biz/Add"], byConsumer["biz_user"]} {
+		for _, v := range []float64{la.M1, la.M5, la.M15} {
+			if v < 9.5 || v > 10.5 {
+				t.Fatalf("bad load average under steady 10 rps: %v", la)
+			}
+		}
+	}
+
+	// минуту тишины: m1 падает сильнее, чем m15
+	byMethod, _ = lt.snapshot(now.Add(time.Minute))
+	if la := byMethod["/main.Biz/Add"]; la.M1 >= la.M5 || la.M5 >= la.M15 {
+		t.Errorf("averages must decay at different speed: %v", la)
+	}
+}
+
+// поток отклонённых вызовов со случайными consumer не раздувает счётчики
+func TestStatConsumerFlood(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.MaxConsumers = 10
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+	w, _ := ss.Subscribe(&StatInterval{IntervalSeconds: 1})
+	defer ss.Unsubscribe(w)
+
+	for i := 0; i < 1000; i++ {
+		e := &Event{Consumer: fmt.Sprintf("random%d", i), Method: "/main.Biz/Check"}
+		ss.Record(e)
+		ss.Reject(e, codes.Unauthenticated)
+	}
+
+	ss.mu.Lock()
+	total, totalCodes := len(ss.total.byConsumer), len(ss.total.codesByConsumer)
+	ss.mu.Unlock()
+	if total > cfg.MaxConsumers+1 || totalCodes > cfg.MaxConsumers+1 {
+		t.Fatalf("consumers are not capped: %d by consumer, %d codes by consumer", total, totalCodes)
+	}
+	for _, st := range []*Stat{ss.Flush(w, time.Now()), mustQuery(t, ss, StatRange_STAT_RANGE_LAST_1M)} {
+		if len(st.ByConsumer) > cfg.MaxConsumers+1 || len(st.CodesByConsumer) > cfg.MaxConsumers+1 {
+			t.Fatalf("consumers are not capped: %d by consumer, %d codes by consumer", len(st.ByConsumer), len(st.CodesByConsumer))
+		}
+		var sum uint64
+		for _, n := range st.ByConsumer {
+			sum += n
+		}
+		if sum != 1000 || st.ByConsumer[otherBucket] < 1000-uint64(cfg.MaxConsumers) {
+			t.Fatalf("calls are lost: %d in total, %d in other", sum, st.ByConsumer[otherBucket])
+		}
+	}
+
+	// отфильтрованный потребитель из "other" не выдаётся за молчащего
+	st, err := ss.Query(&StatQuery{Range: StatRange_STAT_RANGE_SINCE_START, Consumers: []string{"random999", "random0"}}, time.Now())
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(st.FoldedConsumers) != 1 || st.FoldedConsumers[0] != "random999" {
+		t.Fatalf("bad folded consumers %v", st.FoldedConsumers)
+	}
+}
+
+func mustQuery(t *testing.T, ss *SimpleEventStats, r StatRange) *Stat {
+	t.Helper()
+	st, err := ss.Query(&StatQuery{Range: r}, time.Now())
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	return st
+}
+
+// значения, которые при переводе в Duration переполнились бы и попали в допустимый диапазон
+func TestStatIntervalOverflow(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), DefaultStatsConfig())
+	for _, req := range []*StatInterval{
+		{IntervalSeconds: 18446744074},
+		{IntervalMillis: 18446744073709552},
+		{IntervalSeconds: 1, Mode: StatMode_STAT_MODE_SLIDING, WindowSeconds: 18446744074},
+	} {
+		if _, err := ss.Subscribe(req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, have %v", req, err)
+		}
+	}
+}
+
+// пустой StatsConfig, например из WithStatsConfig, получает пределы по умолчанию
+func TestStatsConfigDefaults(t *testing.T) {
+	ss := NewSimpleEventStats(NewSequencer(), StatsConfig{})
+	ss.Record(&Event{Consumer: "biz_user", Method: "/main.Biz/Add"})
+	reply, err := ss.TopK(&TopKQuery{Range: StatRange_STAT_RANGE_SINCE_START}, time.Now())
+	if err != nil || reply.Total != 1 || len(reply.Consumers) != 1 {
+		t.Fatalf("bad top-k with empty config: %v, %v", reply, err)
+	}
+	if _, err := ss.Subscribe(&StatInterval{IntervalSeconds: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
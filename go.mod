@@ -7,6 +7,7 @@ toolchain go1.22.3
 require (
 	google.golang.org/grpc v1.65.0
 	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// 2^10 регистров: около 1 КБ на скетч и ~3% стандартной ошибки
+const hllPrecision = 10
+
+// hyperLogLog оценка числа различных строк. Скетчи с одинаковой точностью
+// сливаются поэлементным максимумом, поэтому их можно объединять между окнами
+// и между инстансами сервера
+type hyperLogLog struct {
+	registers []uint8
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{
+		registers: make([]uint8, 1<<hllPrecision),
+	}
+}
+
+func (h *hyperLogLog) add(v string) {
+	x := hllHash(v)
+	idx := x >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) merge(o *hyperLogLog) {
+	for i, r := range o.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+func (h *hyperLogLog) estimate() uint64 {
+	m := float64(len(h.registers))
+	var sum float64
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	e := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// на малых множествах точнее линейный подсчёт
+	if e <= 2.5*m && zeros > 0 {
+		e = m * math.Log(m/float64(zeros))
+	}
+	return uint64(e + 0.5)
+}
+
+// marshal формат: байт точности, затем по байту на регистр
+func (h *hyperLogLog) marshal() []byte {
+	return append([]byte{hllPrecision}, h.registers...)
+}
+
+func unmarshalHyperLogLog(data []byte) (*hyperLogLog, error) {
+	if len(data) != 1+1<<hllPrecision || data[0] != hllPrecision {
+		return nil, errors.New("unsupported hyperloglog sketch")
+	}
+	return &hyperLogLog{registers: append([]uint8(nil), data[1:]...)}, nil
+}
+
+// hllHash FNV-1a с перемешиванием из splitmix64: у голого FNV плохо распределены старшие биты
+func hllHash(v string) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(v))
+	x := f.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
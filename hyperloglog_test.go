@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHyperLogLogEstimate(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		h := newHyperLogLog()
+		for i := 0; i < n; i++ {
+			h.add(fmt.Sprintf("consumer-%d", i))
+			h.add(fmt.Sprintf("consumer-%d", i)) // повторы не должны влиять
+		}
+		if err := math.Abs(float64(h.estimate())-float64(n)) / float64(n); err > 0.1 {
+			t.Errorf("estimate for %d is %d", n, h.estimate())
+		}
+	}
+}
+
+// объединение скетчей двух инстансов даёт оценку объединения множеств
+func TestHyperLogLogMerge(t *testing.T) {
+	a, b := newHyperLogLog(), newHyperLogLog()
+	for i := 0; i < 3000; i++ {
+		a.add(fmt.Sprintf("consumer-%d", i))
+		b.add(fmt.Sprintf("consumer-%d", i+2000))
+	}
+
+	restored, err := unmarshalHyperLogLog(b.marshal())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a.merge(restored)
+	if err := math.Abs(float64(a.estimate())-5000) / 5000; err > 0.1 {
+		t.Errorf("merged estimate is %d, want ~5000", a.estimate())
+	}
+
+	if _, err := unmarshalHyperLogLog([]byte{4, 1, 2}); err == nil {
+		t.Errorf("expected error on foreign sketch")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// относительная погрешность квантилей
+const sketchRelativeAccuracy = 0.01
+
+// значения меньше этого (в секундах) считаются нулевыми
+const sketchMinValue = 1e-9
+
+// latencySketch упрощённый DDSketch: логарифмические корзины с фиксированной
+// относительной погрешностью. Два скетча складываются без потери точности,
+// поэтому окна можно сливать между собой
+type latencySketch struct {
+	bins  map[int]uint64
+	zeros uint64
+	count uint64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+var sketchLogGamma = math.Log((1 + sketchRelativeAccuracy) / (1 - sketchRelativeAccuracy))
+
+func newLatencySketch() *latencySketch {
+	return &latencySketch{
+		bins: make(map[int]uint64),
+	}
+}
+
+func (s *latencySketch) add(v float64) {
+	if s.count == 0 || v < s.min {
+		s.min = v
+	}
+	if s.count == 0 || v > s.max {
+		s.max = v
+	}
+	s.count++
+	s.sum += v
+
+	if v < sketchMinValue {
+		s.zeros++
+		return
+	}
+	s.bins[int(math.Ceil(math.Log(v)/sketchLogGamma))]++
+}
+
+func (s *latencySketch) merge(o *latencySketch) {
+	if o.count == 0 {
+		return
+	}
+	if s.count == 0 || o.min < s.min {
+		s.min = o.min
+	}
+	if s.count == 0 || o.max > s.max {
+		s.max = o.max
+	}
+	s.count += o.count
+	s.sum += o.sum
+	s.zeros += o.zeros
+	for idx, n := range o.bins {
+		s.bins[idx] += n
+	}
+}
+
+func (s *latencySketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(s.count-1))
+	if rank < s.zeros {
+		return 0
+	}
+
+	keys := make([]int, 0, len(s.bins))
+	for idx := range s.bins {
+		keys = append(keys, idx)
+	}
+	sort.Ints(keys)
+
+	seen := s.zeros
+	for _, idx := range keys {
+		seen += s.bins[idx]
+		if seen > rank {
+			return math.Min(math.Max(sketchBinValue(idx), s.min), s.max)
+		}
+	}
+	return s.max
+}
+
+func (s *latencySketch) toProto() *LatencyStat {
+	return &LatencyStat{
+		Count: s.count,
+		Sum:   s.sum,
+		Min:   s.min,
+		Max:   s.max,
+		P50:   s.quantile(0.5),
+		P90:   s.quantile(0.9),
+		P99:   s.quantile(0.99),
+		P999:  s.quantile(0.999),
+	}
+}
+
+// countBelow приблизительное число значений, не превышающих le
+func (s *latencySketch) countBelow(le float64) uint64 {
+	if le >= s.max {
+		return s.count
+	}
+	n := s.zeros
+	for idx, cnt := range s.bins {
+		if sketchBinValue(idx) <= le {
+			n += cnt
+		}
+	}
+	return n
+}
+
+// sketchBinValue середина корзины, даёт погрешность не больше sketchRelativeAccuracy
+func sketchBinValue(idx int) float64 {
+	return 2 * math.Exp(float64(idx)*sketchLogGamma) / (1 + math.Exp(sketchLogGamma))
+}
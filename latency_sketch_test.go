@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLatencySketchQuantiles(t *testing.T) {
+	sk := newLatencySketch()
+	for i := 1; i <= 10000; i++ {
+		sk.add(float64(i) / 1000)
+	}
+
+	for _, tc := range []struct {
+		q    float64
+		want float64
+	}{
+		{0.5, 5.0},
+		{0.9, 9.0},
+		{0.99, 9.9},
+		{0.999, 9.99},
+	} {
+		have := sk.quantile(tc.q)
+		if math.Abs(have-tc.want)/tc.want > 2*sketchRelativeAccuracy {
+			t.Errorf("p%v: have %v, want %v", tc.q*100, have, tc.want)
+		}
+	}
+	if sk.min != 0.001 || sk.max != 10 {
+		t.Errorf("bad bounds: min %v max %v", sk.min, sk.max)
+	}
+}
+
+// слияние двух половин даёт тот же результат, что и один общий скетч
+func TestLatencySketchMerge(t *testing.T) {
+	whole, left, right := newLatencySketch(), newLatencySketch(), newLatencySketch()
+	for i := 1; i <= 1000; i++ {
+		v := float64(i) / 1000
+		whole.add(v)
+		if i%2 == 0 {
+			left.add(v)
+		} else {
+			right.add(v)
+		}
+	}
+	left.merge(right)
+
+	if left.count != whole.count || left.min != whole.min || left.max != whole.max {
+		t.Fatalf("merged summary differs: %+v vs %+v", left.toProto(), whole.toProto())
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		if left.quantile(q) != whole.quantile(q) {
+			t.Errorf("p%v differs after merge: %v vs %v", q*100, left.quantile(q), whole.quantile(q))
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// по умолчанию сервер пингует молчащего клиента раз в минуту, так что мёртвые
+// подписчики Logging отваливаются за time + timeout, а не по таймауту TCP через часы
+var (
+	defaultKeepalive = keepalive.ServerParameters{
+		Time:    time.Minute,
+		Timeout: 20 * time.Second,
+	}
+	defaultKeepalivePolicy = keepalive.EnforcementPolicy{
+		MinTime:             10 * time.Second,
+		PermitWithoutStream: true,
+	}
+)
+
+// при наплыве соединений в журнал попадает не больше одного отказа за это время,
+// остальные только считаются, иначе журнал и подписчики Logging захлебнулись бы
+const rejectLogInterval = time.Second
+
+// connLimiter общий для всех листенеров предел одновременных соединений
+type connLimiter struct {
+	max    int64
+	active atomic.Int64
+	logger EventLogger
+
+	mu         sync.Mutex
+	lastLogged time.Time
+	suppressed int
+}
+
+// wrap лишние соединения закрываются сразу после Accept, до рукопожатия HTTP/2,
+// и попадают в журнал как EVENT_KIND_CONNECTION_REJECTED
+func (cl *connLimiter) wrap(l net.Listener) net.Listener {
+	return &limitListener{Listener: l, cl: cl}
+}
+
+type limitListener struct {
+	net.Listener
+	cl *connLimiter
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.cl.active.Add(1) <= l.cl.max {
+			return &limitConn{Conn: conn, release: func() { l.cl.active.Add(-1) }}, nil
+		}
+		l.cl.active.Add(-1)
+		conn.Close()
+		l.cl.reject(conn.RemoteAddr(), l.Addr())
+	}
+}
+
+// reject журналирует отказ, если с прошлой записи прошло rejectLogInterval.
+// Интервал считается по реальному времени, с фиксированными часами записан был бы только первый отказ
+func (cl *connLimiter) reject(host, listener net.Addr) {
+	now := time.Now()
+	cl.mu.Lock()
+	if now.Sub(cl.lastLogged) < rejectLogInterval {
+		cl.suppressed++
+		cl.mu.Unlock()
+		return
+	}
+	suppressed := cl.suppressed
+	cl.lastLogged, cl.suppressed = now, 0
+	cl.mu.Unlock()
+
+	if suppressed > 0 {
+		log.Printf("%d more connections rejected since the last logged one", suppressed)
+	}
+	cl.logger.LogEvent("", "", formatAddr(host), formatAddr(listener), EventKind_EVENT_KIND_CONNECTION_REJECTED)
+}
+
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+type rpcStateKey struct{}
+
+// rpcState что limitStats знает о вызове от TagRPC до End
+type rpcState struct {
+	method string
+	// перехватчики ACL видели вызов, то есть запрос gRPC уже принял
+	intercepted atomic.Bool
+}
+
+func rpcStateFrom(ctx context.Context) *rpcState {
+	st, _ := ctx.Value(rpcStateKey{}).(*rpcState)
+	return st
+}
+
+// markIntercepted вызывают перехватчики ACL первым делом
+func markIntercepted(ctx context.Context) {
+	if st := rpcStateFrom(ctx); st != nil {
+		st.intercepted.Store(true)
+	}
+}
+
+// limitStats stats.Handler, который журналирует слишком большие запросы унарных методов:
+// gRPC отклоняет их с ResourceExhausted ещё до перехватчиков, поэтому видно их только здесь.
+// Остальные нарушения размера ловят перехватчики, см. sizeStream и checkSendSize.
+//
+// Журналируются только отказы в соединении и нарушения размера сообщений.
+// GOAWAY за слишком частые пинги, закрытие по keepalive, max_idle и max_age и отказ
+// в потоке сверх max_streams gRPC наружу не сообщает, в журнал они не попадают
+type limitStats struct {
+	d *authDeps
+}
+
+func (h limitStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcStateKey{}, &rpcState{method: info.FullMethodName})
+}
+
+func (h limitStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	end, ok := s.(*stats.End)
+	st := rpcStateFrom(ctx)
+	if !ok || st == nil || st.intercepted.Load() || status.Code(end.Error) != codes.ResourceExhausted {
+		return
+	}
+	h.d.messageTooLarge(ctx, st.method)
+}
+
+func (h limitStats) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h limitStats) HandleConn(ctx context.Context, s stats.ConnStats) {}
+
+func (d *authDeps) messageTooLarge(ctx context.Context, method string) {
+	consumer, _ := d.identify(ctx)
+	host, listener := peerAddrs(ctx)
+	d.logger.LogEvent(consumer, method, host, listener, EventKind_EVENT_KIND_MESSAGE_TOO_LARGE)
+}
+
+// checkSendSize ответ больше предела на отправку gRPC отклонит уже после перехватчика,
+// поэтому размер сравнивается с пределом заранее
+func (d *authDeps) checkSendSize(ctx context.Context, method string, m interface{}) {
+	if msg, ok := m.(proto.Message); ok && d.maxSendMsgSize > 0 && proto.Size(msg) > d.maxSendMsgSize {
+		d.messageTooLarge(ctx, method)
+	}
+}
+
+// sizeStream журналирует нарушения размера в потоковых вызовах. Ошибку RecvMsg
+// возвращает сам gRPC, поэтому ResourceExhausted там - всегда предел на приём
+type sizeStream struct {
+	grpc.ServerStream
+	d      *authDeps
+	method string
+}
+
+func (s sizeStream) SendMsg(m interface{}) error {
+	s.d.checkSendSize(s.Context(), s.method, m)
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s sizeStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if status.Code(err) == codes.ResourceExhausted {
+		s.d.messageTooLarge(s.Context(), s.method)
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/peer"
+)
+
+// права сокета по умолчанию: владелец и группа, чтобы сайдкар из той же группы мог подключиться
+const defaultSocketMode os.FileMode = 0o660
+
+const unixPrefix = "unix:"
+
+// listen открывает адрес вида host:port, [::1]:port или unix-сокет по соглашению gRPC:
+// unix:relative/path, unix:/abs/path или unix:///abs/path.
+// Оставшийся от прошлого запуска файл сокета удаляется, но только если его никто
+// не слушает; обычный файл не трогается
+func listen(addr string, mode os.FileMode) (net.Listener, error) {
+	if !validListenAddr(addr) {
+		return nil, fmt.Errorf("bad listen address %q, want host:port, unix:path or unix:///abs/path", addr)
+	}
+	path, ok := socketPath(addr)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("listen unix %s: address already in use", path)
+		}
+		os.Remove(path)
+	}
+	return listenUnix(path, mode)
+}
+
+// listenUnix сокет создаётся в закрытом каталоге рядом с path, получает mode и
+// только потом переименовывается в path, так что доступнее, чем задано, он не бывает.
+// umask процесса не меняется: она общая для всех горутин
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// файл удаляет socketListener.Close под настоящим именем
+	l.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &socketListener{Listener: l, addr: &net.UnixAddr{Name: path, Net: "unix"}}, nil
+}
+
+// socketListener ядро помнит временное имя сокета, поэтому адрес листенера
+// и локальный адрес соединений подменяются на path
+type socketListener struct {
+	net.Listener
+	addr *net.UnixAddr
+}
+
+func (l *socketListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &socketConn{Conn: conn, addr: l.addr}, nil
+}
+
+func (l *socketListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *socketListener) Close() error {
+	err := l.Listener.Close()
+	os.Remove(l.addr.Name)
+	return err
+}
+
+type socketConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *socketConn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+// socketPath путь из unix-адреса. После unix:// идёт пустой authority,
+// поэтому путь там только абсолютный, а unix:path - относительно рабочего каталога
+func socketPath(addr string) (path string, ok bool) {
+	path, ok = strings.CutPrefix(addr, unixPrefix)
+	if !ok {
+		return "", false
+	}
+	if abs, found := strings.CutPrefix(path, "//"); found {
+		if !strings.HasPrefix(abs, "/") {
+			return "", true
+		}
+		path = abs
+	}
+	return path, true
+}
+
+// validListenAddr адрес, который listen примет: net.Listen("tcp", "") открыл бы
+// случайный порт на всех интерфейсах, поэтому пустой адрес и пустой порт - ошибка
+func validListenAddr(addr string) bool {
+	if path, ok := socketPath(addr); ok {
+		return strings.Trim(path, "/") != ""
+	}
+	_, port, err := net.SplitHostPort(addr)
+	return err == nil && port != ""
+}
+
+// formatAddr адрес в том же виде, в каком листенеры задают в конфигурации
+func formatAddr(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if addr.Network() == "unix" {
+		return unixPrefix + addr.String()
+	}
+	return addr.String()
+}
+
+// peerAddrs адрес клиента и листенер, на который пришёл вызов.
+// У клиента unix-сокета адреса обычно нет, тогда host - просто "unix:"
+func peerAddrs(ctx context.Context) (host, listener string) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	return formatAddr(p.Addr), formatAddr(p.LocalAddr)
+}
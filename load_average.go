@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// как и в Unix, средние обновляются раз в 5 секунд
+const loadTick = 5 * time.Second
+
+// после такого простоя все средние всё равно практически нулевые
+const loadMaxCatchUp = 15 * time.Minute * 10
+
+var loadAlphas = [3]float64{
+	1 - math.Exp(-float64(loadTick)/float64(time.Minute)),
+	1 - math.Exp(-float64(loadTick)/float64(5*time.Minute)),
+	1 - math.Exp(-float64(loadTick)/float64(15*time.Minute)),
+}
+
+type ewma struct {
+	pending uint64
+	rates   [3]float64
+}
+
+// loadTracker экспоненциальные средние частоты вызовов за 1, 5 и 15 минут.
+// Отдельной горутины нет: пропущенные тики досчитываются при следующем обращении
+type loadTracker struct {
+	maxConsumers int
+	last         time.Time
+	byMethod     map[string]*ewma
+	byConsumer   map[string]*ewma
+}
+
+func newLoadTracker(maxConsumers int, now time.Time) *loadTracker {
+	return &loadTracker{
+		maxConsumers: maxConsumers,
+		last:         now,
+		byMethod:     make(map[string]*ewma),
+		byConsumer:   make(map[string]*ewma),
+	}
+}
+
+func (lt *loadTracker) add(e *Event, now time.Time) {
+	lt.advance(now)
+	m, ok := lt.byMethod[e.Method]
+	if !ok {
+		m = &ewma{}
+		lt.byMethod[e.Method] = m
+	}
+	m.pending++
+
+	consumer := e.Consumer
+	c, ok := lt.byConsumer[consumer]
+	if !ok {
+		if len(lt.byConsumer) >= lt.maxConsumers {
+			consumer = otherBucket
+			c = lt.byConsumer[consumer]
+		}
+		if c == nil {
+			c = &ewma{}
+			lt.byConsumer[consumer] = c
+		}
+	}
+	c.pending++
+}
+
+func (lt *loadTracker) advance(now time.Time) {
+	elapsed := now.Sub(lt.last)
+	if elapsed < loadTick {
+		return
+	}
+	if elapsed > loadMaxCatchUp {
+		lt.byMethod = make(map[string]*ewma)
+		lt.byConsumer = make(map[string]*ewma)
+		lt.last = now
+		return
+	}
+	for ; !lt.last.Add(loadTick).After(now); lt.last = lt.last.Add(loadTick) {
+		tickLoad(lt.byMethod)
+		tickLoad(lt.byConsumer)
+	}
+}
+
+func tickLoad(m map[string]*ewma) {
+	for key, l := range m {
+		instant := float64(l.pending) / loadTick.Seconds()
+		l.pending = 0
+		idle := true
+		for i, alpha := range loadAlphas {
+			l.rates[i] += alpha * (instant - l.rates[i])
+			if l.rates[i] > 1e-9 {
+				idle = false
+			}
+		}
+		// затухшие ключи не держим
+		if idle {
+			delete(m, key)
+		}
+	}
+}
+
+func (lt *loadTracker) snapshot(now time.Time) (byMethod, byConsumer map[string]*LoadAverage) {
+	lt.advance(now)
+	return loadToProto(lt.byMethod), loadToProto(lt.byConsumer)
+}
+
+func loadToProto(m map[string]*ewma) map[string]*LoadAverage {
+	res := make(map[string]*LoadAverage, len(m))
+	for key, l := range m {
+		res[key] = &LoadAverage{M1: l.rates[0], M5: l.rates[1], M15: l.rates[2]}
+	}
+	return res
+}
+
+// fillRates частоты вызовов за окно сообщения
+func fillRates(st *Stat, window time.Duration) {
+	st.RateByMethod = make(map[string]float64, len(st.ByMethod))
+	st.RateByConsumer = make(map[string]float64, len(st.ByConsumer))
+	if window <= 0 {
+		return
+	}
+	for method, n := range st.ByMethod {
+		st.RateByMethod[method] = float64(n) / window.Seconds()
+	}
+	for consumer, n := range st.ByConsumer {
+		st.RateByConsumer[consumer] = float64(n) / window.Seconds()
+	}
+}
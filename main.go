@@ -1,5 +1,195 @@
 package main
 
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+)
+
+const usage = `usage:
+  hw7_microservice serve [-config FILE] [-acl FILE] [-addr HOST:PORT]
+  hw7_microservice config print [-config FILE]
+  hw7_microservice acl validate FILE
+  hw7_microservice version
+`
+
 func main() {
-	println("usage: make test")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run разбирает подкоманду и возвращает код выхода
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "serve":
+		return runServe(args[1:], stderr)
+	case "config":
+		if len(args) < 2 || args[1] != "print" {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return runConfigPrint(args[2:], stdout, stderr)
+	case "acl":
+		if len(args) != 3 || args[1] != "validate" {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return runACLValidate(args[2], stdout, stderr)
+	case "version":
+		printVersion(stdout)
+		return 0
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+}
+
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "127.0.0.1:8082", "gRPC listen address, host:port, unix:path or unix:///abs/path, overrides listen.addr")
+	aclPath := fs.String("acl", "", "ACL file, JSON consumer -> methods, overrides acl.file")
+	configPath := fs.String("config", "", "YAML config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := LoadConfig(*configPath, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 1
+	}
+	// флаги важнее файла и окружения, но только если заданы явно
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Listen.Addr = *addr
+		case "acl":
+			cfg.ACL = ACLConfig{File: *aclPath}
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 2
+	}
+	aclData, err := cfg.ACLData()
+	if err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 1
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 1
+	}
+
+	// первый сигнал запускает плавную остановку, повторный завершает процесс сразу
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	srv, err := NewServer(ctx, cfg.Listen.Addr, aclData, opts...)
+	if err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 1
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if err := srv.Wait(); err != nil {
+		fmt.Fprintln(stderr, "serve:", err)
+		return 1
+	}
+	return 0
+}
+
+// runConfigPrint печатает итоговую конфигурацию после файла и окружения, без секретов
+func runConfigPrint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "YAML config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cfg, err := LoadConfig(*configPath, os.LookupEnv)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	out, err := cfg.Redacted()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	stdout.Write(out)
+	return 0
+}
+
+func runACLValidate(path string, stdout, stderr io.Writer) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	acl, err := ParseACL(string(data))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	warnings := acl.Lint()
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "%s: %s\n", path, w)
+	}
+	if len(warnings) > 0 {
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: ok, %d consumers\n", path, len(acl))
+	return 0
+}
+
+// buildVersion версия одной строкой, для Admin.Describe
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(no build info)"
+	}
+	version := info.Main.Path + " " + info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			version += " " + s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				version += " (modified)"
+			}
+		}
+	}
+	return version
+}
+
+// printVersion версия модуля и ревизия, которые go build записывает в бинарник
+func printVersion(w io.Writer) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Fprintln(w, "hw7_microservice (no build info)")
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", info.Main.Path, info.Main.Version)
+	fmt.Fprintf(w, "go: %s\n", info.GoVersion)
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			fmt.Fprintf(w, "%s: %s\n", s.Key, s.Value)
+		}
+	}
 }
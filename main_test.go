@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestCLIACLValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(ACLData), 0o644)
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"biz_user": ["/main.Biz/Check", "/main.Biz/Check", "/main.Biz/Nope"], "nobody": []}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"acl", "validate", good}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected valid acl, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"acl", "validate", bad}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected lint failure, got %d", code)
+	}
+	for _, want := range []string{"duplicate", "/main.Biz/Nope", "nobody"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("no warning about %s in %q", want, stderr.String())
+		}
+	}
+
+	if code := run([]string{"frobnicate"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("unknown command must print usage, got %d", code)
+	}
+}
+
+// флаг -addr важнее listen.addr из файла, остальное берётся из файла; SIGTERM - плавная остановка с кодом 0
+func TestCLIServe(t *testing.T) {
+	dir := t.TempDir()
+	aclPath := filepath.Join(dir, "acl.json")
+	os.WriteFile(aclPath, []byte(ACLData), 0o644)
+	configPath := filepath.Join(dir, "config.yaml")
+	fileAddr, flagAddr := freeAddr(t), freeAddr(t)
+	os.WriteFile(configPath, []byte(fmt.Sprintf("listen:\n  addr: %s\nacl:\n  file: %s\n", fileAddr, aclPath)), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	exit := make(chan int, 1)
+	go func() {
+		exit <- run([]string{"serve", "-config", configPath, "-addr", flagAddr}, &stdout, &stderr)
+	}()
+
+	conn, err := grpc.Dial(flagAddr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(getConsumerCtx("biz_user"), 3*time.Second)
+	defer cancel()
+	if _, err := NewBizClient(conn).Check(ctx, &Nothing{}, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("server from config is not serving: %v", err)
+	}
+	if c, err := net.Dial("tcp", fileAddr); err == nil {
+		c.Close()
+		t.Fatalf("-addr must override listen.addr")
+	}
+
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	select {
+	case code := <-exit:
+		if code != 0 {
+			t.Fatalf("expected graceful stop, got %d: %s", code, stderr.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("serve did not stop on SIGTERM")
+	}
+}
+
+func TestCLIVersion(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"version"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("version failed with %d", code)
+	}
+	if !strings.Contains(stdout.String(), "go: ") && !strings.Contains(stdout.String(), "no build info") {
+		t.Fatalf("bad version output %q", stdout.String())
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cant listen: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	metricsPrefix = "async_logger_"
+
+	contentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// границы корзин гистограммы времени выполнения, секунды
+var latencyBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metricSample struct {
+	suffix string
+	labels [][2]string
+	value  float64
+}
+
+type metricFamily struct {
+	name    string // для счётчиков без суффикса _total
+	help    string
+	typ     string // counter, gauge, histogram
+	samples []metricSample
+}
+
+// MetricsHandler отдаёт /metrics в формате Prometheus или OpenMetrics
+// в зависимости от заголовка Accept. Данные берутся из того же накопителя,
+// который наполняют перехватчики для Admin.Statistics
+type MetricsHandler struct {
+	logger EventLogger
+	stats  *SimpleEventStats
+}
+
+// loggerCounters счётчики подписчиков, как у SimpleEventLogger;
+// для логгера без них метрики потока Logging не отдаются
+type loggerCounters interface {
+	Subscribers() int
+	Dropped() map[string]uint64
+}
+
+func NewMetricsHandler(logger EventLogger, stats *SimpleEventStats) *MetricsHandler {
+	return &MetricsHandler{
+		logger: logger,
+		stats:  stats,
+	}
+}
+
+func (mh *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", contentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", contentTypeText)
+	}
+
+	bw := bufio.NewWriter(w)
+	writeMetricFamilies(bw, mh.collect(), openMetrics)
+	bw.Flush()
+}
+
+func (mh *MetricsHandler) collect() []metricFamily {
+	acc := mh.stats.snapshot()
+
+	calls := metricFamily{name: "calls", help: "Calls received, by consumer and method.", typ: "counter"}
+	for consumer, byMethod := range acc.byConsumerMethod {
+		for method, n := range byMethod {
+			calls.samples = append(calls.samples, metricSample{
+				suffix: "_total",
+				labels: [][2]string{{"consumer", consumer}, {"method", method}},
+				value:  float64(n),
+			})
+		}
+	}
+
+	handled := metricFamily{name: "handled", help: "Calls finished, by consumer, method and gRPC code.", typ: "counter"}
+	for _, gc := range acc.groups {
+		handled.samples = append(handled.samples, metricSample{
+			suffix: "_total",
+			labels: [][2]string{{"consumer", gc.values[0]}, {"method", gc.values[1]}, {"code", gc.values[2]}},
+			value:  float64(gc.count),
+		})
+	}
+
+	latency := metricFamily{name: "handler_duration_seconds", help: "Handler latency, by method.", typ: "histogram"}
+	for method, sk := range acc.latencyByMethod {
+		for _, le := range latencyBuckets {
+			latency.samples = append(latency.samples, metricSample{
+				suffix: "_bucket",
+				labels: [][2]string{{"method", method}, {"le", formatFloat(le)}},
+				value:  float64(sk.countBelow(le)),
+			})
+		}
+		latency.samples = append(latency.samples,
+			metricSample{suffix: "_bucket", labels: [][2]string{{"method", method}, {"le", "+Inf"}}, value: float64(sk.count)},
+			metricSample{suffix: "_sum", labels: [][2]string{{"method", method}}, value: sk.sum},
+			metricSample{suffix: "_count", labels: [][2]string{{"method", method}}, value: float64(sk.count)},
+		)
+	}
+
+	streams := metricFamily{name: "admin_streams", help: "Open Admin streams, by kind.", typ: "gauge"}
+	streams.samples = []metricSample{
+		{labels: [][2]string{{"stream", "statistics"}}, value: float64(mh.stats.Windows())},
+	}
+
+	dropped := metricFamily{name: "subscriber_dropped_events", help: "Events not delivered to slow subscribers, by Logging streams and the notifier.", typ: "counter"}
+	if lc, ok := mh.logger.(loggerCounters); ok {
+		streams.samples = append(streams.samples, metricSample{labels: [][2]string{{"stream", streamLogging}}, value: float64(lc.Subscribers())})
+		for stream, n := range lc.Dropped() {
+			dropped.samples = append(dropped.samples, metricSample{
+				suffix: "_total",
+				labels: [][2]string{{"stream", stream}},
+				value:  float64(n),
+			})
+		}
+	}
+
+	return []metricFamily{calls, handled, latency, streams, dropped}
+}
+
+func writeMetricFamilies(w io.Writer, families []metricFamily, openMetrics bool) {
+	for _, f := range families {
+		sortSamples(f.samples)
+		name := metricsPrefix + f.name
+		if f.typ == "counter" && !openMetrics {
+			// в текстовом формате Prometheus семейство счётчика называется вместе с _total
+			name += "_total"
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, f.typ)
+		for _, s := range f.samples {
+			fmt.Fprintf(w, "%s%s%s %s\n", metricsPrefix+f.name, s.suffix, formatLabels(s.labels), formatFloat(s.value))
+		}
+	}
+	if openMetrics {
+		fmt.Fprint(w, "# EOF\n")
+	}
+}
+
+// sortSamples делает вывод стабильным: по меткам, корзины гистограммы по возрастанию le
+func sortSamples(samples []metricSample) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return formatLabels(withoutLe(samples[i].labels)) < formatLabels(withoutLe(samples[j].labels))
+	})
+}
+
+func withoutLe(labels [][2]string) [][2]string {
+	if len(labels) > 0 && labels[len(labels)-1][0] == "le" {
+		return labels[:len(labels)-1]
+	}
+	return labels
+}
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l[0] + `="` + escapeLabelValue(l[1]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	seq := NewSequencer()
+	logger := NewSimpleEventLogger(seq, SystemClock{})
+	stats := NewSimpleEventStats(seq, DefaultStatsConfig())
+
+	e := logger.LogEvent("biz_user", "/main.Biz/Add", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_CALL)
+	stats.Record(e)
+	stats.Complete(e, codes.OK, 20*time.Millisecond)
+	ch := logger.Subscribe()
+	defer logger.Unsubscribe(ch)
+
+	handler := NewMetricsHandler(logger, stats)
+
+	for _, tc := range []struct {
+		accept      string
+		contentType string
+		want        []string
+	}{
+		{
+			accept:      "text/plain",
+			contentType: contentTypeText,
+			want: []string{
+				"# TYPE async_logger_calls_total counter",
+				`async_logger_calls_total{consumer="biz_user",method="/main.Biz/Add"} 1`,
+				`async_logger_handled_total{consumer="biz_user",method="/main.Biz/Add",code="OK"} 1`,
+				`async_logger_handler_duration_seconds_bucket{method="/main.Biz/Add",le="0.01"} 0`,
+				`async_logger_handler_duration_seconds_bucket{method="/main.Biz/Add",le="0.025"} 1`,
+				`async_logger_admin_streams{stream="logging"} 1`,
+			},
+		},
+		{
+			accept:      "application/openmetrics-text; version=1.0.0",
+			contentType: contentTypeOpenMetrics,
+			want: []string{
+				"# TYPE async_logger_calls counter",
+				`async_logger_calls_total{consumer="biz_user",method="/main.Biz/Add"} 1`,
+				"# EOF",
+			},
+		},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", tc.accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Errorf("bad content type for %q: %s", tc.accept, ct)
+		}
+		body := rec.Body.String()
+		for _, line := range tc.want {
+			if !strings.Contains(body, line+"\n") {
+				t.Errorf("no %q in output for %q:\n%s", line, tc.accept, body)
+			}
+		}
+	}
+}
+
+// потери уведомлений считаются отдельно от потоков Logging
+func TestMetricsDroppedByStream(t *testing.T) {
+	seq := NewSequencer()
+	logger := NewSimpleEventLogger(seq, SystemClock{})
+	logger.buffer = 1
+	n, err := NewNotifier(NotifierConfig{}, logger)
+	if err != nil {
+		t.Fatalf("cannot create notifier: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n.Start(ctx)
+	if _, ok := logger.Dropped()[streamNotifier]; !ok || logger.Subscribers() != 0 {
+		t.Fatalf("notifier must subscribe as its own stream: %v, %d", logger.Dropped(), logger.Subscribers())
+	}
+	cancel()
+	n.Wait()
+
+	// подписка уведомлений, которая не успевает разбирать события
+	ch := logger.SubscribeStream(streamNotifier)
+	defer logger.Unsubscribe(ch)
+	for i := 0; i < 3; i++ {
+		logger.LogEvent("biz_user", "/main.Biz/Add", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_CALL)
+	}
+
+	rec := httptest.NewRecorder()
+	NewMetricsHandler(logger, NewSimpleEventStats(seq, DefaultStatsConfig())).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		`async_logger_subscriber_dropped_events_total{stream="notifier"} 2`,
+		`async_logger_admin_streams{stream="logging"} 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("no %q in output:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// сколько доставок может ждать свободного отправителя
+	notifierQueue = 1024
+	// одновременных доставок, чтобы один медленный адрес не задерживал остальные
+	notifierWorkers = 4
+
+	signatureHeader = "X-Signature-256"
+	timestampHeader = "X-Signature-Timestamp"
+)
+
+// шаблон тела по умолчанию
+const defaultNotifyTemplate = `{"rule": {{json .Rule}}, "event": {{json .Event}}}`
+
+// NotifyRule какие события куда отправлять. Пустой список условий совпадает с любым значением
+type NotifyRule struct {
+	Name string `json:"name"`
+	// имена EventKind: EVENT_KIND_CALL, EVENT_KIND_DENIED
+	Kinds     []string `json:"kinds"`
+	Consumers []string `json:"consumers"`
+	// полные имена методов или маски вида /main.Biz/*
+	Methods []string `json:"methods"`
+	URL     string   `json:"url"`
+	// text/template над {Rule, Event}; функция json вставляет значение как JSON
+	Template string `json:"template"`
+	// ключ HMAC-SHA256. Подписывается строка <timestamp>.<тело>, где timestamp - unix-секунды
+	// из заголовка X-Signature-Timestamp; подпись уходит в X-Signature-256 как sha256=<hex>.
+	// Получатель, отвергающий старые timestamp, защищён от повтора перехваченного запроса
+	Secret string `json:"secret"`
+}
+
+// NotifierConfig правила и политика повторов
+type NotifierConfig struct {
+	Rules []NotifyRule `json:"rules"`
+	// сюда построчно в JSON пишутся доставки, от которых пришлось отказаться; пусто - только в лог
+	DeadLetterPath string `json:"dead_letter_path"`
+	MaxAttempts    int    `json:"max_attempts"`
+	// задержка перед вторым запросом, дальше удваивается до MaxBackoffMs
+	BackoffMs    int `json:"backoff_ms"`
+	MaxBackoffMs int `json:"max_backoff_ms"`
+	TimeoutMs    int `json:"timeout_ms"`
+}
+
+// LoadNotifierConfig читает конфигурацию из JSON-файла
+func LoadNotifierConfig(path string) (NotifierConfig, error) {
+	cfg := NotifierConfig{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// withDefaults незаданные параметры повторов
+func (cfg NotifierConfig) withDefaults() NotifierConfig {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.BackoffMs <= 0 {
+		cfg.BackoffMs = 500
+	}
+	if cfg.MaxBackoffMs < cfg.BackoffMs {
+		cfg.MaxBackoffMs = 30000
+	}
+	if cfg.TimeoutMs <= 0 {
+		cfg.TimeoutMs = 5000
+	}
+	return cfg
+}
+
+type notifyRule struct {
+	NotifyRule
+	kinds []EventKind
+	tmpl  *template.Template
+}
+
+type delivery struct {
+	Rule     string    `json:"rule"`
+	URL      string    `json:"url"`
+	Body     string    `json:"body"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	Time     time.Time `json:"time"`
+	secret   string
+}
+
+// Notifier подписывается на EventLogger и отправляет совпавшие с правилами
+// события вебхуками. Перехватчики его не ждут: при переполнении очереди события
+// теряются на стороне логгера и считаются в subscriber_dropped_events{stream="notifier"}
+// на /metrics, а доставки уходят в dead letter
+type Notifier struct {
+	cfg    NotifierConfig
+	rules  []*notifyRule
+	logger EventLogger
+	client *http.Client
+	queue  chan *delivery
+	dlMu   sync.Mutex
+
+	// закрывается, когда в очередь больше ничего не попадёт
+	dispatched chan struct{}
+	running    sync.WaitGroup
+}
+
+func NewNotifier(cfg NotifierConfig, logger EventLogger) (*Notifier, error) {
+	cfg = cfg.withDefaults()
+	n := &Notifier{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+		queue:  make(chan *delivery, notifierQueue),
+
+		dispatched: make(chan struct{}),
+	}
+	funcs := template.FuncMap{"json": templateJSON}
+	for _, r := range cfg.Rules {
+		if r.URL == "" {
+			return nil, fmt.Errorf("notify rule %q: url is required", r.Name)
+		}
+		text := r.Template
+		if text == "" {
+			text = defaultNotifyTemplate
+		}
+		tmpl, err := template.New(r.Name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("notify rule %q: %v", r.Name, err)
+		}
+		rule := &notifyRule{NotifyRule: r, tmpl: tmpl}
+		for _, k := range r.Kinds {
+			kind, ok := EventKind_value[k]
+			if !ok {
+				return nil, fmt.Errorf("notify rule %q: unknown event kind %q", r.Name, k)
+			}
+			rule.kinds = append(rule.kinds, EventKind(kind))
+		}
+		n.rules = append(n.rules, rule)
+	}
+	return n, nil
+}
+
+// Start подписывается на события сразу, а разбирает и доставляет их в фоне до отмены ctx.
+// После отмены всё, что не успели отправить, уходит в dead letter, см. Wait
+func (n *Notifier) Start(ctx context.Context) {
+	var ch chan *Event
+	if ss, ok := n.logger.(streamSubscriber); ok {
+		ch = ss.SubscribeStream(streamNotifier)
+	} else {
+		ch = n.logger.Subscribe()
+	}
+	n.running.Add(1 + notifierWorkers)
+	go func() {
+		defer n.running.Done()
+		defer close(n.dispatched)
+		defer n.logger.Unsubscribe(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				// события, уже полученные от логгера, тоже попадают в очередь
+				for {
+					select {
+					case e := <-ch:
+						n.dispatch(e)
+					default:
+						return
+					}
+				}
+			case e := <-ch:
+				n.dispatch(e)
+			}
+		}
+	}()
+	for i := 0; i < notifierWorkers; i++ {
+		go n.work(ctx)
+	}
+}
+
+// Wait ждёт после отмены контекста Start, пока все недоставленные уведомления
+// окажутся в dead letter
+func (n *Notifier) Wait() {
+	n.running.Wait()
+}
+
+func (n *Notifier) dispatch(e *Event) {
+	for _, r := range n.rules {
+		if !r.match(e) {
+			continue
+		}
+		d := &delivery{Rule: r.Name, URL: r.URL, secret: r.Secret}
+		body, err := r.render(e)
+		if err != nil {
+			d.Error = err.Error()
+			n.deadLetter(d)
+			continue
+		}
+		d.Body = body
+		select {
+		case n.queue <- d:
+		default:
+			d.Error = "queue is full"
+			n.deadLetter(d)
+		}
+	}
+}
+
+func (n *Notifier) work(ctx context.Context) {
+	defer n.running.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			// недоставленное не теряется молча; очередь разбирается, когда
+			// диспетчер уже ничего в неё не положит
+			<-n.dispatched
+			for {
+				select {
+				case d := <-n.queue:
+					d.Error = "server is stopping"
+					n.deadLetter(d)
+				default:
+					return
+				}
+			}
+		case d := <-n.queue:
+			n.deliver(ctx, d)
+		}
+	}
+}
+
+// deliver повторяет запрос с экспоненциальной задержкой, пока адрес отвечает
+// ошибкой сети, 5xx или 429; остальные коды считаются окончательным отказом
+func (n *Notifier) deliver(ctx context.Context, d *delivery) {
+	backoff := time.Duration(n.cfg.BackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(n.cfg.MaxBackoffMs) * time.Millisecond
+	for {
+		d.Attempts++
+		retry, err := n.post(ctx, d)
+		if err == nil {
+			return
+		}
+		d.Error = err.Error()
+		if !retry || d.Attempts >= n.cfg.MaxAttempts {
+			n.deadLetter(d)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			n.deadLetter(d)
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, d *delivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, strings.NewReader(d.Body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.secret != "" {
+		// каждая попытка подписывается заново со своим временем
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(timestampHeader, ts)
+		req.Header.Set(signatureHeader, "sha256="+sign(d.secret, ts, d.Body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+func (n *Notifier) deadLetter(d *delivery) {
+	log.Printf("Cannot deliver notification %q to %s after %d attempts: %s", d.Rule, d.URL, d.Attempts, d.Error)
+	if n.cfg.DeadLetterPath == "" {
+		return
+	}
+	d.Time = time.Now()
+	line, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	n.dlMu.Lock()
+	defer n.dlMu.Unlock()
+	f, err := os.OpenFile(n.cfg.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Println("Cannot open dead letter file: ", err)
+		return
+	}
+	defer f.Close()
+	f.Write(append(line, '\n'))
+}
+
+func (r *notifyRule) match(e *Event) bool {
+	return matchAny(r.kinds, e.GetKind(), func(a, b EventKind) bool { return a == b }) &&
+		matchAny(r.Consumers, e.GetConsumer(), func(a, b string) bool { return a == b }) &&
+		matchAny(r.Methods, e.GetMethod(), matchMethod)
+}
+
+func matchAny[T any](patterns []T, v T, eq func(p, v T) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if eq(p, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMethod точное совпадение или маска /package.Service/*
+func matchMethod(pattern, method string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(method, prefix)
+	}
+	return pattern == method
+}
+
+func (r *notifyRule) render(e *Event) (string, error) {
+	var buf bytes.Buffer
+	err := r.tmpl.Execute(&buf, struct {
+		Rule  string
+		Event *Event
+	}{r.Name, e})
+	if err != nil {
+		return "", err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return "", fmt.Errorf("template rendered invalid JSON: %s", buf.String())
+	}
+	return buf.String(), nil
+}
+
+func templateJSON(v interface{}) (string, error) {
+	var data []byte
+	var err error
+	if m, ok := v.(proto.Message); ok {
+		data, err = protojson.Marshal(m)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	return string(data), err
+}
+
+func sign(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// подписанное тело по шаблону доходит после повторов
+func TestNotifierDelivery(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		ts, err := strconv.ParseInt(r.Header.Get(timestampHeader), 10, 64)
+		if err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+			t.Errorf("bad timestamp %q", r.Header.Get(timestampHeader))
+		}
+		if r.Header.Get(signatureHeader) != "sha256="+sign("s3cret", r.Header.Get(timestampHeader), string(body)) {
+			t.Errorf("bad signature %q", r.Header.Get(signatureHeader))
+		}
+		received <- string(body)
+	}))
+	defer srv.Close()
+
+	logger := NewSimpleEventLogger(NewSequencer(), SystemClock{})
+	n, err := NewNotifier(NotifierConfig{
+		Rules: []NotifyRule{{
+			Name:     "denied",
+			Kinds:    []string{"EVENT_KIND_DENIED"},
+			Methods:  []string{"/main.Biz/*"},
+			URL:      srv.URL,
+			Template: `{"text": {{json (printf "%s denied on %s" .Event.Consumer .Event.Method)}}}`,
+			Secret:   "s3cret",
+		}},
+		BackoffMs: 1,
+	}, logger)
+	if err != nil {
+		t.Fatalf("cannot create notifier: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n.Start(ctx)
+
+	logger.LogEvent("biz_user", "/main.Biz/Add", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_CALL)
+	logger.LogEvent("biz_user", "/main.Admin/Logging", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_DENIED)
+	logger.LogEvent("biz_user", "/main.Biz/Test", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_DENIED)
+
+	select {
+	case body := <-received:
+		if body != `{"text": "biz_user denied on /main.Biz/Test"}` {
+			t.Fatalf("bad body: %s", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("notification was not delivered")
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+// после последней попытки доставка пишется в dead letter
+func TestNotifierDeadLetter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	logger := NewSimpleEventLogger(NewSequencer(), SystemClock{})
+	n, err := NewNotifier(NotifierConfig{
+		Rules:          []NotifyRule{{Name: "all", URL: srv.URL}},
+		DeadLetterPath: path,
+		MaxAttempts:    2,
+		BackoffMs:      1,
+	}, logger)
+	if err != nil {
+		t.Fatalf("cannot create notifier: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n.Start(ctx)
+
+	logger.LogEvent("biz_user", "/main.Biz/Add", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_CALL)
+
+	var data []byte
+	for i := 0; i < 100 && len(data) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		data, _ = os.ReadFile(path)
+	}
+	var d delivery
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("bad dead letter %q: %v", data, err)
+	}
+	if d.Rule != "all" || d.Attempts != 2 || !strings.Contains(d.Body, "/main.Biz/Add") {
+		t.Fatalf("bad dead letter: %+v", d)
+	}
+}
+
+// при остановке доставки в работе и в очереди дописываются в dead letter
+func TestNotifierStopDrains(t *testing.T) {
+	var started atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started.Add(1)
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	logger := NewSimpleEventLogger(NewSequencer(), SystemClock{})
+	n, err := NewNotifier(NotifierConfig{
+		Rules:          []NotifyRule{{Name: "all", URL: srv.URL}},
+		DeadLetterPath: path,
+	}, logger)
+	if err != nil {
+		t.Fatalf("cannot create notifier: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n.Start(ctx)
+
+	const total = 2 * notifierWorkers
+	for i := 0; i < total; i++ {
+		logger.LogEvent("biz_user", "/main.Biz/Add", "127.0.0.1:1234", "127.0.0.1:8082", EventKind_EVENT_KIND_CALL)
+	}
+	for i := 0; i < 100 && started.Load() < notifierWorkers; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	n.Wait()
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != total {
+		t.Fatalf("expected %d dead letters, got %d:\n%s", total, lines, data)
+	}
+}
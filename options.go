@@ -0,0 +1,209 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Option необязательная настройка микросервиса
+type Option func(*serviceOptions)
+
+type serviceOptions struct {
+	metricsAddr     string
+	alertRulesPath  string
+	notifierPath    string
+	sloPath         string
+	shutdownTimeout time.Duration
+	tls             *tls.Config
+	logger          EventLogger
+	stats           EventStats
+	unary           []grpc.UnaryServerInterceptor
+	stream          []grpc.StreamServerInterceptor
+	listener        net.Listener
+	clock           Clock
+	identify        IdentityResolver
+	statsConfig     *StatsConfig
+	loggerBuffer    int
+	healthACL       bool
+	listen          []string
+	socketMode      os.FileMode
+	maxConns        int
+	maxStreams      uint32
+	maxRecvMsgSize  int
+	maxSendMsgSize  int
+	keepalive       *keepalive.ServerParameters
+	keepalivePolicy *keepalive.EnforcementPolicy
+}
+
+// WithMetricsAddr поднимает HTTP-листенер с /metrics на указанном адресе
+func WithMetricsAddr(addr string) Option {
+	return func(o *serviceOptions) {
+		o.metricsAddr = addr
+	}
+}
+
+// WithAlertRules читает правила алертов из JSON-файла с сообщением AlertRules;
+// правки через Admin сохраняются в тот же файл
+func WithAlertRules(path string) Option {
+	return func(o *serviceOptions) {
+		o.alertRulesPath = path
+	}
+}
+
+// WithNotifier отправляет события вебхуками по правилам из JSON-файла с NotifierConfig
+func WithNotifier(path string) Option {
+	return func(o *serviceOptions) {
+		o.notifierPath = path
+	}
+}
+
+// WithSLOs объявляет цели по методам из JSON-файла с сообщением SLOs
+func WithSLOs(path string) Option {
+	return func(o *serviceOptions) {
+		o.sloPath = path
+	}
+}
+
+// WithShutdownTimeout сколько при остановке ждать вызовы в работе,
+// прежде чем оборвать их; по умолчанию 10 секунд
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *serviceOptions) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithTLS принимает вызовы только по TLS с указанной конфигурацией
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *serviceOptions) {
+		o.tls = cfg
+	}
+}
+
+// WithEventLogger своя реализация журнала вызовов вместо SimpleEventLogger
+func WithEventLogger(logger EventLogger) Option {
+	return func(o *serviceOptions) {
+		o.logger = logger
+	}
+}
+
+// WithStats своя реализация статистики вместо SimpleEventStats;
+// Subscribe должен возвращать окно из NewStatWindow.
+// Алерты, WithSLOs и /metrics считаются по SimpleEventStats и с другой реализацией недоступны
+func WithStats(stats EventStats) Option {
+	return func(o *serviceOptions) {
+		o.stats = stats
+	}
+}
+
+// WithExtraInterceptors перехватчики, которые выполняются в указанном порядке
+// после проверки ACL, то есть только для разрешённых вызовов
+func WithExtraInterceptors(unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor) Option {
+	return func(o *serviceOptions) {
+		o.unary = append(o.unary, unary...)
+		o.stream = append(o.stream, stream...)
+	}
+}
+
+// WithListener обслуживает готовый листенер вместо addr;
+// сервер закрывает его при остановке
+func WithListener(listener net.Listener) Option {
+	return func(o *serviceOptions) {
+		o.listener = listener
+	}
+}
+
+// WithClock источник времени для встроенных логгера и статистики, нужен в тестах
+func WithClock(clock Clock) Option {
+	return func(o *serviceOptions) {
+		o.clock = clock
+	}
+}
+
+// WithIdentityResolver как определять потребителя вместо метаданного consumer,
+// например по сертификату клиента. Вызов, для которого resolver вернул ошибку,
+// отклоняется с ней и учитывается под потребителем "unknown"
+func WithIdentityResolver(identify IdentityResolver) Option {
+	return func(o *serviceOptions) {
+		o.identify = identify
+	}
+}
+
+// WithStatsConfig ограничения встроенной статистики вместо DefaultStatsConfig;
+// SLOs и Clock берутся из WithSLOs и WithClock
+func WithStatsConfig(cfg StatsConfig) Option {
+	return func(o *serviceOptions) {
+		o.statsConfig = &cfg
+	}
+}
+
+// WithLoggerBuffer сколько событий копится у медленного подписчика встроенного журнала
+func WithLoggerBuffer(n int) Option {
+	return func(o *serviceOptions) {
+		o.loggerBuffer = n
+	}
+}
+
+// WithHealthCheckACL проверять ACL у grpc.health.v1.Health, как у остальных методов;
+// по умолчанию проверки здоровья доступны без consumer
+func WithHealthCheckACL(enabled bool) Option {
+	return func(o *serviceOptions) {
+		o.healthACL = enabled
+	}
+}
+
+// WithListen дополнительные адреса того же сервера: host:port, [::1]:port
+// или unix:path (unix:///abs/path для абсолютного). События и статистика различают их по Event.listener
+func WithListen(addrs ...string) Option {
+	return func(o *serviceOptions) {
+		o.listen = append(o.listen, addrs...)
+	}
+}
+
+// WithSocketMode права файлов unix-сокетов, по умолчанию 0660
+func WithSocketMode(mode os.FileMode) Option {
+	return func(o *serviceOptions) {
+		o.socketMode = mode
+	}
+}
+
+// WithMaxConnections предел одновременных соединений на все листенеры;
+// лишние закрываются сразу и журналируются как EVENT_KIND_CONNECTION_REJECTED,
+// не чаще раза в секунду
+func WithMaxConnections(n int) Option {
+	return func(o *serviceOptions) {
+		o.maxConns = n
+	}
+}
+
+// WithMaxStreams предел одновременных вызовов в одном соединении,
+// остальные клиент держит в очереди; в журнал это не попадает
+func WithMaxStreams(n uint32) Option {
+	return func(o *serviceOptions) {
+		o.maxStreams = n
+	}
+}
+
+// WithMaxMessageSize пределы размера сообщений в байтах, 0 - по умолчанию gRPC:
+// 4 МиБ на приём и без предела на отправку. Нарушения журналируются как
+// EVENT_KIND_MESSAGE_TOO_LARGE
+func WithMaxMessageSize(recv, send int) Option {
+	return func(o *serviceOptions) {
+		o.maxRecvMsgSize = recv
+		o.maxSendMsgSize = send
+	}
+}
+
+// WithKeepalive пинги сервера, ограничения возраста соединений и политика
+// для пингов клиента; по умолчанию сервер пингует молчащее соединение раз в минуту.
+// Соединения, закрытые по этим правилам, не журналируются: gRPC не сообщает причину
+func WithKeepalive(params keepalive.ServerParameters, policy keepalive.EnforcementPolicy) Option {
+	return func(o *serviceOptions) {
+		o.keepalive = &params
+		o.keepalivePolicy = &policy
+	}
+}
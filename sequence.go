@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Sequencer раздаёт событиям и статистике общий монотонный номер
+// и помечает их идентификатором инстанса сервера
+type Sequencer struct {
+	instanceID string
+	last       atomic.Uint64
+}
+
+func NewSequencer() *Sequencer {
+	return &Sequencer{
+		instanceID: newInstanceID(),
+	}
+}
+
+func (s *Sequencer) Next() uint64 {
+	return s.last.Add(1)
+}
+
+func (s *Sequencer) InstanceID() string {
+	return s.instanceID
+}
+
+func (s *Sequencer) StampEvent(e *Event, now time.Time) {
+	e.Timestamp = now.Unix()
+	e.Time = timestamppb.New(now)
+	e.Seq = s.Next()
+	e.InstanceId = s.instanceID
+}
+
+func (s *Sequencer) StampStat(st *Stat, now time.Time) {
+	st.Timestamp = now.Unix()
+	st.Time = timestamppb.New(now)
+	st.Seq = s.Next()
+	st.InstanceId = s.instanceID
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// события и статистика одного сервера делят последовательность и instance_id,
+// время хранится с наносекундами, а старое поле timestamp - в секундах
+func TestSequencerStamps(t *testing.T) {
+	seq := NewSequencer()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+
+	e := &Event{}
+	seq.StampEvent(e, now)
+	st := &Stat{}
+	seq.StampStat(st, now)
+
+	if e.Seq != 1 || st.Seq != 2 {
+		t.Fatalf("bad seq: event %d, stat %d", e.Seq, st.Seq)
+	}
+	if e.Timestamp != now.Unix() || !e.Time.AsTime().Equal(now) || !st.Time.AsTime().Equal(now) {
+		t.Fatalf("bad time: %d, %v, %v", e.Timestamp, e.Time.AsTime(), st.Time.AsTime())
+	}
+	if e.InstanceId == "" || e.InstanceId != st.InstanceId || e.InstanceId != seq.InstanceID() {
+		t.Fatalf("bad instance id: %q, %q", e.InstanceId, st.InstanceId)
+	}
+	if other := NewSequencer(); other.InstanceID() == seq.InstanceID() {
+		t.Fatalf("instances share id %q", seq.InstanceID())
+	}
+}
+
+func TestSequencerUnique(t *testing.T) {
+	seq := NewSequencer()
+	const workers, per = 8, 1000
+	seen := make(chan uint64, workers*per)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < per; j++ {
+				e := &Event{}
+				seq.StampEvent(e, time.Now())
+				seen <- e.Seq
+			}
+		}()
+	}
+	wg.Wait()
+	close(seen)
+
+	uniq := make(map[uint64]bool, workers*per)
+	for n := range seen {
+		if uniq[n] || n == 0 || n > workers*per {
+			t.Fatalf("seq %d is repeated or out of range", n)
+		}
+		uniq[n] = true
+	}
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server запущенный микросервис
+type Server struct {
+	listeners       []net.Listener
+	grpc            *grpc.Server
+	health          *health.Server
+	acl             ACL
+	logger          EventLogger
+	stats           EventStats
+	alerts          *AlertManager // nil при WithStats
+	notifier        *Notifier
+	drain           *drainState
+	shutdownTimeout time.Duration
+
+	cancel   context.CancelFunc // останавливает фоновые подсистемы
+	ready    chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	stopErr  error
+
+	serveErrOnce sync.Once
+	serveErr     error
+}
+
+// NewServer слушает addr и начинает обслуживать вызовы в фоне.
+// Ошибки разбора ACL, конфигурации и Listen возвращаются сразу.
+// Отмена ctx запускает плавную остановку с таймаутом WithShutdownTimeout
+func NewServer(ctx context.Context, addr string, ACLData string, opts ...Option) (*Server, error) {
+	options := &serviceOptions{
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	acl, err := ParseACL(ACLData)
+	if err != nil {
+		log.Println("Invalid ACL data: " + ACLData)
+		return nil, err
+	}
+
+	clock := options.clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	seq := NewSequencer()
+
+	logger := options.logger
+	if logger == nil {
+		simpleLogger := NewSimpleEventLogger(seq, clock)
+		if options.loggerBuffer > 0 {
+			simpleLogger.buffer = options.loggerBuffer
+		}
+		logger = simpleLogger
+	}
+
+	built := false
+	stats := options.stats
+	if stats == nil {
+		statsConfig := DefaultStatsConfig()
+		if options.statsConfig != nil {
+			statsConfig = *options.statsConfig
+		}
+		statsConfig.Clock = clock
+		if options.sloPath != "" {
+			statsConfig.SLOs, err = LoadSLOs(options.sloPath)
+			if err != nil {
+				log.Println("Invalid SLOs: ", err)
+				return nil, err
+			}
+		}
+		own := NewSimpleEventStats(seq, statsConfig)
+		// если сервер не соберётся, фоновое сохранение истории останавливается,
+		// а файл истории остаётся как был
+		defer func() {
+			if !built {
+				own.stopPersist()
+			}
+		}()
+		stats = own
+	}
+	simpleStats, _ := stats.(*SimpleEventStats)
+	if simpleStats == nil && (options.sloPath != "" || options.alertRulesPath != "" || options.metricsAddr != "") {
+		return nil, fmt.Errorf("SLOs, alerts and metrics require the built-in statistics")
+	}
+	if options.stats != nil && options.sloPath != "" {
+		return nil, fmt.Errorf("SLOs of custom statistics are set in StatsConfig")
+	}
+
+	var alerts *AlertManager
+	if simpleStats != nil {
+		alerts, err = NewAlertManager(simpleStats, options.alertRulesPath)
+		if err != nil {
+			log.Println("Invalid alert rules: ", err)
+			return nil, err
+		}
+	}
+
+	var notifier *Notifier
+	if options.notifierPath != "" {
+		cfg, err := LoadNotifierConfig(options.notifierPath)
+		if err == nil {
+			notifier, err = NewNotifier(cfg, logger)
+		}
+		if err != nil {
+			log.Println("Invalid notifier config: ", err)
+			return nil, err
+		}
+	}
+
+	socketMode := options.socketMode
+	if socketMode == 0 {
+		socketMode = defaultSocketMode
+	}
+	listeners := []net.Listener{options.listener}
+	if options.listener == nil {
+		listeners = listeners[:0]
+		options.listen = append([]string{addr}, options.listen...)
+	}
+	for _, a := range options.listen {
+		listener, err := listen(a, socketMode)
+		if err != nil {
+			log.Println("Cannot listen port: ", err)
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	identify := options.identify
+	if identify == nil {
+		identify = getConsumerName
+	}
+
+	deps := &authDeps{
+		acl:      acl,
+		identify: identify,
+		logger:   logger,
+		stats:    stats,
+		drain:    newDrainState(),
+		clock:    clock,
+
+		healthACL:      options.healthACL,
+		maxSendMsgSize: options.maxSendMsgSize,
+	}
+
+	keepaliveParams, keepalivePolicy := defaultKeepalive, defaultKeepalivePolicy
+	if options.keepalive != nil {
+		keepaliveParams, keepalivePolicy = *options.keepalive, *options.keepalivePolicy
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{unaryAuthInterceptor(deps)}, options.unary...)...),
+		grpc.ChainStreamInterceptor(append([]grpc.StreamServerInterceptor{streamAuthInterceptor(deps)}, options.stream...)...),
+		grpc.StatsHandler(limitStats{d: deps}),
+		grpc.KeepaliveParams(keepaliveParams),
+		grpc.KeepaliveEnforcementPolicy(keepalivePolicy),
+	}
+	if options.maxStreams > 0 {
+		serverOpts = append(serverOpts, grpc.MaxConcurrentStreams(options.maxStreams))
+	}
+	if options.maxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(options.maxRecvMsgSize))
+	}
+	if options.maxSendMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(options.maxSendMsgSize))
+	}
+	if options.maxConns > 0 {
+		limiter := &connLimiter{max: int64(options.maxConns), logger: logger}
+		for i, l := range listeners {
+			listeners[i] = limiter.wrap(l)
+		}
+	}
+	if options.tls != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(options.tls)))
+	}
+	server := grpc.NewServer(serverOpts...)
+
+	bizModule := getBizInstance()
+	adminModule := getAdminInstance(deps, alerts, server, listeners)
+
+	RegisterBizServer(server, bizModule)
+	RegisterAdminServer(server, adminModule)
+
+	// пустое имя - сервер целиком, его health.NewServer уже считает SERVING
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(Biz_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(Admin_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	// для grpcurl и подобных; вызовы рефлексии проверяются по ACL как обычные
+	reflection.Register(server)
+
+	// фоновые подсистемы живут до конца Shutdown, даже если ctx не отменяли
+	bgCtx, cancel := context.WithCancel(context.Background())
+
+	if options.metricsAddr != "" {
+		err = serveMetrics(bgCtx, options.metricsAddr, NewMetricsHandler(logger, simpleStats))
+		if err != nil {
+			cancel()
+			closeListeners(listeners)
+			return nil, err
+		}
+	}
+
+	s := &Server{
+		listeners:       listeners,
+		grpc:            server,
+		health:          healthServer,
+		acl:             acl,
+		logger:          logger,
+		stats:           stats,
+		alerts:          alerts,
+		notifier:        notifier,
+		drain:           deps.drain,
+		shutdownTimeout: options.shutdownTimeout,
+		cancel:          cancel,
+		ready:           make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+
+	serving := &sync.WaitGroup{}
+	serving.Add(len(listeners))
+	for _, listener := range listeners {
+		fmt.Println("starting server at ", formatAddr(listener.Addr()))
+		go func(l *servingListener) {
+			err := server.Serve(l)
+			l.once.Do(serving.Done)
+			// ErrServerStopped - остановка успела раньше Serve, это не ошибка
+			if err != nil && err != grpc.ErrServerStopped {
+				log.Println("Cannot accept connection: ", err)
+				s.serveErrOnce.Do(func() {
+					s.serveErr = err
+				})
+				// как и при отмене ctx: зависший вызов не должен держать остановку вечно
+				timeout, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+				defer cancel()
+				s.Shutdown(timeout)
+			}
+		}(&servingListener{Listener: listener, serving: serving})
+	}
+	go func() {
+		serving.Wait()
+		close(s.ready)
+	}()
+	if alerts != nil {
+		go alerts.Run(bgCtx)
+	}
+	if notifier != nil {
+		notifier.Start(bgCtx)
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			timeout, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+			defer cancel()
+			s.Shutdown(timeout)
+		case <-s.done:
+		}
+	}()
+
+	built = true
+	return s, nil
+}
+
+// Addr адрес первого листенера, который реально слушает сервер, в том числе при порте 0
+func (s *Server) Addr() net.Addr {
+	return s.listeners[0].Addr()
+}
+
+// Addrs адреса всех листенеров в порядке addr, WithListen
+func (s *Server) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.listeners))
+	for i, l := range s.listeners {
+		addrs[i] = l.Addr()
+	}
+	return addrs
+}
+
+// Ready закрывается, когда каждый листенер передан в Serve и ждёт соединений
+// или Serve уже вернулся, потому что сервер успели остановить
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Wait ждёт полной остановки сервера и возвращает ошибку Serve, если она была
+func (s *Server) Wait() error {
+	<-s.done
+	return s.serveErr
+}
+
+// Shutdown плавно останавливает сервер; вызовы, не завершившиеся до отмены ctx,
+// обрываются, и тогда возвращается ошибка ctx. Повторные вызовы ждут первую остановку
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		// NOT_SERVING раньше отказов, чтобы балансировщик успел убрать сервер
+		s.health.Shutdown()
+		if !gracefulShutdown(ctx, s.grpc, s.drain, s.logger, formatAddr(s.Addr())) {
+			s.stopErr = ctx.Err()
+		}
+		s.cancel()
+		if s.notifier != nil {
+			s.notifier.Wait()
+		}
+		err := s.stats.Close()
+		if err != nil {
+			log.Println("Cannot save statistics history: ", err)
+		}
+		log.Println("Shutdown complete")
+		close(s.done)
+	})
+	<-s.done
+	return s.stopErr
+}
+
+func (s *Server) ACL() ACL {
+	return s.acl
+}
+
+func (s *Server) Logger() EventLogger {
+	return s.logger
+}
+
+func (s *Server) Stats() EventStats {
+	return s.stats
+}
+
+func (s *Server) Alerts() *AlertManager {
+	return s.alerts
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, l := range listeners {
+		l.Close()
+	}
+}
+
+// servingListener отмечает первый Accept: его вызывает Serve, когда листенер
+// уже зарегистрирован и вызовы на нём будут обслужены
+type servingListener struct {
+	net.Listener
+	once    sync.Once
+	serving *sync.WaitGroup
+}
+
+func (l *servingListener) Accept() (net.Conn, error) {
+	l.once.Do(l.serving.Done)
+	return l.Listener.Accept()
+}
@@ -2,25 +2,27 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"log"
 	"net"
+	"net/http"
 	"strings"
-	"sync"
+	"time"
 )
 
 var (
 	authKey            = "consumer"
-	defaultHost        = "127.0.0.1"
 	errMissingMetadata = status.Errorf(codes.InvalidArgument, "missing metadata")
 	errInvalidConsumer = status.Errorf(codes.Unauthenticated, "invalid consumer")
 )
 
+// под этим именем учитываются вызовы, потребителя которых определить не удалось
+const unknownConsumer = "unknown"
+
 func getConsumerName(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -33,134 +35,184 @@ func getConsumerName(ctx context.Context) (string, error) {
 	return consumers[0], nil
 }
 
-func authorize(ctx context.Context, method string, acl map[string][]string) (bool, error) {
-	consumer, err := getConsumerName(ctx)
-	if err != nil {
-		return false, err
+// IdentityResolver определяет потребителя по контексту вызова; по умолчанию
+// берётся метаданное consumer
+type IdentityResolver func(ctx context.Context) (string, error)
+
+// eventKind вид события по результату проверки ACL
+func eventKind(aclErr error) EventKind {
+	if aclErr != nil {
+		return EventKind_EVENT_KIND_DENIED
 	}
+	return EventKind_EVENT_KIND_CALL
+}
 
-	methods, ok := acl[consumer]
-	if !ok || len(methods) == 0 {
-		return false, errInvalidConsumer
+// authDeps всё, что нужно перехватчикам
+type authDeps struct {
+	acl      ACL
+	identify IdentityResolver
+	logger   EventLogger
+	stats    EventStats
+	drain    *drainState
+	clock    Clock
+	// проверять ли ACL у вызовов grpc.health.v1.Health; по умолчанию они открыты всем
+	healthACL bool
+	// предел на отправку, 0 - без предела
+	maxSendMsgSize int
+}
+
+// begin проверяет доступ и учитывает начало вызова
+func (d *authDeps) begin(ctx context.Context, method string) (*Event, error) {
+	if d.drain.draining.Load() {
+		return nil, errShuttingDown
 	}
-	for _, m := range methods {
-		fullM := strings.Split(m, "/")
-		if strings.HasSuffix(method, fullM[1]+"/"+fullM[2]) || (fullM[2] == "*" && strings.Contains(method, fullM[1])) {
-			return true, nil
+
+	// вызов без потребителя тоже попадает в журнал и в разбивку по кодам
+	name, err := d.identify(ctx)
+	if err != nil {
+		name = unknownConsumer
+	} else {
+		var ok bool
+		ok, err = d.acl.Allowed(name, method)
+		if err == nil && !ok {
+			err = errInvalidConsumer
 		}
 	}
-	return false, nil
+
+	host, listener := peerAddrs(ctx)
+	e := d.logger.LogEvent(name, method, host, listener, eventKind(err))
+	d.stats.Record(e)
+	if err != nil {
+		d.stats.Reject(e, status.Code(err))
+		return nil, err
+	}
+	return e, nil
 }
 
-func streamAuthInterceptor(acl map[string][]string, host string, logger *SimpleEventLogger, stats *SimpleEventStats) grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		name, errCtx := getConsumerName(context.Background())
+// isHealthMethod проверки здоровья не журналируются, не попадают в статистику
+// и работают во время остановки, чтобы оркестратор увидел NOT_SERVING
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
 
-		if errCtx != nil {
-			return errCtx
-		}
+func (d *authDeps) checkHealth(ctx context.Context, method string) error {
+	if !d.healthACL {
+		return nil
+	}
+	name, err := d.identify(ctx)
+	if err != nil {
+		return err
+	}
+	ok, err := d.acl.Allowed(name, method)
+	if err == nil && !ok {
+		err = errInvalidConsumer
+	}
+	return err
+}
 
-		logger.LogEvent(name, info.FullMethod, host)
-		ok, err := authorize(ss.Context(), info.FullMethod, acl)
+// drainingStream контекст потока отменяется при остановке сервера, иначе
+// Health/Watch держал бы GracefulStop до таймаута
+type drainingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
 
+func (s drainingStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamAuthInterceptor(d *authDeps) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		markIntercepted(ss.Context())
+		if isHealthMethod(info.FullMethod) {
+			if err := d.checkHealth(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithCancel(ss.Context())
+			defer cancel()
+			go func() {
+				select {
+				case <-d.drain.done:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			return handler(srv, drainingStream{ServerStream: ss, ctx: ctx})
+		}
+
+		e, err := d.begin(ss.Context(), info.FullMethod)
 		if err != nil {
 			return err
 		}
-		if !ok {
-			return errInvalidConsumer
-		}
-		return handler(srv, ss)
+
+		d.drain.inFlight.Add(1)
+		defer d.drain.inFlight.Add(-1)
+
+		start := time.Now()
+		err = handler(srv, sizeStream{ServerStream: ss, d: d, method: info.FullMethod})
+		d.stats.Complete(e, status.Code(err), time.Since(start))
+		return err
 	}
 }
 
-func unaryAuthInterceptor(acl map[string][]string, host string, logger *SimpleEventLogger, stats *SimpleEventStats) grpc.UnaryServerInterceptor {
+func unaryAuthInterceptor(d *authDeps) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		name, errCtx := getConsumerName(context.Background())
-
-		if errCtx != nil {
-			return nil, errCtx
+		markIntercepted(ctx)
+		if isHealthMethod(info.FullMethod) {
+			if err := d.checkHealth(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
 		}
 
-		logger.LogEvent(name, info.FullMethod, host)
-
-		ok, err := authorize(ctx, info.FullMethod, acl)
+		e, err := d.begin(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
-		if !ok {
-			return nil, errInvalidConsumer
-		}
 
-		return handler(ctx, req)
+		d.drain.inFlight.Add(1)
+		defer d.drain.inFlight.Add(-1)
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		if err == nil {
+			d.checkSendSize(ctx, info.FullMethod, resp)
+		}
+		d.stats.Complete(e, status.Code(err), time.Since(start))
+		return resp, err
 	}
 }
 
-// StartMyMicroservice начальная точка входа
-func StartMyMicroservice(ctx context.Context, addr string, ACLData string) error {
-	acl := make(map[string][]string)
+// StartMyMicroservice начальная точка входа; сервер останавливается отменой ctx
+func StartMyMicroservice(ctx context.Context, addr string, ACLData string, opts ...Option) error {
+	_, err := NewServer(ctx, addr, ACLData, opts...)
+	return err
+}
 
-	err := json.Unmarshal([]byte(ACLData), &acl)
-	if err != nil {
-		log.Println("Invalid ACL data: " + ACLData)
-		return err
-	}
+const metricsHeaderTimeout = 5 * time.Second
 
+func serveMetrics(ctx context.Context, addr string, handler http.Handler) error {
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Println("Cannot listen port: ", err)
-	}
-
-	logger := &SimpleEventLogger{
-		mu:          sync.Mutex{},
-		subscribers: make(map[chan *Event]struct{}),
-	}
-
-	stats := &SimpleEventStats{
-		subscribers: make(map[chan *Event]struct{}),
-		stats: Stat{
-			ByMethod:   make(map[string]uint64),
-			ByConsumer: make(map[string]uint64),
-		},
-	}
-
-	hostPort := strings.Split(addr, ":")
-
-	var host string
-	if len(hostPort) == 2 {
-		host = hostPort[0] + ":"
-	} else {
-		host = defaultHost + ":"
+		log.Println("Cannot listen metrics port: ", err)
+		return err
 	}
 
-	server := grpc.NewServer(
-		grpc.UnaryInterceptor(unaryAuthInterceptor(acl, host, logger, stats)),
-		grpc.StreamInterceptor(streamAuthInterceptor(acl, host, logger, stats)))
-
-	bizModule := getBizInstance()
-	adminModule := getAdminInstance(host, logger, stats)
-
-	RegisterBizServer(server, bizModule)
-	RegisterAdminServer(server, adminModule)
-
-	fmt.Println("starting server at ", addr)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	// без таймаута заголовков медленный клиент держал бы соединение сколько угодно
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: metricsHeaderTimeout}
 
 	go func() {
 		err := server.Serve(listener)
-		if err != nil {
-			log.Println("Cannot accept connection: ", err)
+		if err != nil && err != http.ErrServerClosed {
+			log.Println("Cannot serve metrics: ", err)
 		}
 	}()
-	go ServerStopper(ctx, server)
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
 
 	return nil
 }
-
-func ServerStopper(ctx context.Context, server *grpc.Server) {
-	for {
-		select {
-		case <-ctx.Done():
-			server.Stop()
-			return
-		}
-	}
-}
@@ -27,8 +27,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Admin_Logging_FullMethodName    = "/main.Admin/Logging"
-	Admin_Statistics_FullMethodName = "/main.Admin/Statistics"
+	Admin_Logging_FullMethodName         = "/main.Admin/Logging"
+	Admin_Statistics_FullMethodName      = "/main.Admin/Statistics"
+	Admin_GetStatistics_FullMethodName   = "/main.Admin/GetStatistics"
+	Admin_TopK_FullMethodName            = "/main.Admin/TopK"
+	Admin_History_FullMethodName         = "/main.Admin/History"
+	Admin_Alerts_FullMethodName          = "/main.Admin/Alerts"
+	Admin_ListAlertRules_FullMethodName  = "/main.Admin/ListAlertRules"
+	Admin_SetAlertRule_FullMethodName    = "/main.Admin/SetAlertRule"
+	Admin_DeleteAlertRule_FullMethodName = "/main.Admin/DeleteAlertRule"
+	Admin_GetSLOs_FullMethodName         = "/main.Admin/GetSLOs"
+	Admin_Describe_FullMethodName        = "/main.Admin/Describe"
 )
 
 // AdminClient is the client API for Admin service.
@@ -37,6 +46,15 @@ const (
 type AdminClient interface {
 	Logging(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
 	Statistics(ctx context.Context, in *StatInterval, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Stat], error)
+	GetStatistics(ctx context.Context, in *StatQuery, opts ...grpc.CallOption) (*Stat, error)
+	TopK(ctx context.Context, in *TopKQuery, opts ...grpc.CallOption) (*TopKReply, error)
+	History(ctx context.Context, in *HistoryQuery, opts ...grpc.CallOption) (*HistoryReply, error)
+	Alerts(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error)
+	ListAlertRules(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*AlertRules, error)
+	SetAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*Nothing, error)
+	DeleteAlertRule(ctx context.Context, in *AlertRuleName, opts ...grpc.CallOption) (*Nothing, error)
+	GetSLOs(ctx context.Context, in *SLOQuery, opts ...grpc.CallOption) (*SLOReport, error)
+	Describe(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*Description, error)
 }
 
 type adminClient struct {
@@ -85,12 +103,120 @@ func (c *adminClient) Statistics(ctx context.Context, in *StatInterval, opts ...
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Admin_StatisticsClient = grpc.ServerStreamingClient[Stat]
 
+func (c *adminClient) GetStatistics(ctx context.Context, in *StatQuery, opts ...grpc.CallOption) (*Stat, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stat)
+	err := c.cc.Invoke(ctx, Admin_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) TopK(ctx context.Context, in *TopKQuery, opts ...grpc.CallOption) (*TopKReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopKReply)
+	err := c.cc.Invoke(ctx, Admin_TopK_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) History(ctx context.Context, in *HistoryQuery, opts ...grpc.CallOption) (*HistoryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryReply)
+	err := c.cc.Invoke(ctx, Admin_History_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Alerts(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Admin_ServiceDesc.Streams[2], Admin_Alerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Nothing, Alert]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Admin_AlertsClient = grpc.ServerStreamingClient[Alert]
+
+func (c *adminClient) ListAlertRules(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*AlertRules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRules)
+	err := c.cc.Invoke(ctx, Admin_ListAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*Nothing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Nothing)
+	err := c.cc.Invoke(ctx, Admin_SetAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteAlertRule(ctx context.Context, in *AlertRuleName, opts ...grpc.CallOption) (*Nothing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Nothing)
+	err := c.cc.Invoke(ctx, Admin_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetSLOs(ctx context.Context, in *SLOQuery, opts ...grpc.CallOption) (*SLOReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SLOReport)
+	err := c.cc.Invoke(ctx, Admin_GetSLOs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Describe(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*Description, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Description)
+	err := c.cc.Invoke(ctx, Admin_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServer is the server API for Admin service.
 // All implementations must embed UnimplementedAdminServer
 // for forward compatibility.
 type AdminServer interface {
 	Logging(*Nothing, grpc.ServerStreamingServer[Event]) error
 	Statistics(*StatInterval, grpc.ServerStreamingServer[Stat]) error
+	GetStatistics(context.Context, *StatQuery) (*Stat, error)
+	TopK(context.Context, *TopKQuery) (*TopKReply, error)
+	History(context.Context, *HistoryQuery) (*HistoryReply, error)
+	Alerts(*Nothing, grpc.ServerStreamingServer[Alert]) error
+	ListAlertRules(context.Context, *Nothing) (*AlertRules, error)
+	SetAlertRule(context.Context, *AlertRule) (*Nothing, error)
+	DeleteAlertRule(context.Context, *AlertRuleName) (*Nothing, error)
+	GetSLOs(context.Context, *SLOQuery) (*SLOReport, error)
+	Describe(context.Context, *Nothing) (*Description, error)
 	mustEmbedUnimplementedAdminServer()
 }
 
@@ -107,6 +233,33 @@ func (UnimplementedAdminServer) Logging(*Nothing, grpc.ServerStreamingServer[Eve
 func (UnimplementedAdminServer) Statistics(*StatInterval, grpc.ServerStreamingServer[Stat]) error {
 	return status.Errorf(codes.Unimplemented, "method Statistics not implemented")
 }
+func (UnimplementedAdminServer) GetStatistics(context.Context, *StatQuery) (*Stat, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedAdminServer) TopK(context.Context, *TopKQuery) (*TopKReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopK not implemented")
+}
+func (UnimplementedAdminServer) History(context.Context, *HistoryQuery) (*HistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
+}
+func (UnimplementedAdminServer) Alerts(*Nothing, grpc.ServerStreamingServer[Alert]) error {
+	return status.Errorf(codes.Unimplemented, "method Alerts not implemented")
+}
+func (UnimplementedAdminServer) ListAlertRules(context.Context, *Nothing) (*AlertRules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertRules not implemented")
+}
+func (UnimplementedAdminServer) SetAlertRule(context.Context, *AlertRule) (*Nothing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAlertRule not implemented")
+}
+func (UnimplementedAdminServer) DeleteAlertRule(context.Context, *AlertRuleName) (*Nothing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedAdminServer) GetSLOs(context.Context, *SLOQuery) (*SLOReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLOs not implemented")
+}
+func (UnimplementedAdminServer) Describe(context.Context, *Nothing) (*Description, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
 func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
 func (UnimplementedAdminServer) testEmbeddedByValue()               {}
 
@@ -150,13 +303,201 @@ func _Admin_Statistics_Handler(srv interface{}, stream grpc.ServerStream) error
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Admin_StatisticsServer = grpc.ServerStreamingServer[Stat]
 
+func _Admin_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetStatistics(ctx, req.(*StatQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_TopK_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopKQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).TopK(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_TopK_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).TopK(ctx, req.(*TopKQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_History_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).History(ctx, req.(*HistoryQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Alerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Nothing)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServer).Alerts(m, &grpc.GenericServerStream[Nothing, Alert]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Admin_AlertsServer = grpc.ServerStreamingServer[Alert]
+
+func _Admin_ListAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Nothing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListAlertRules(ctx, req.(*Nothing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_SetAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetAlertRule(ctx, req.(*AlertRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRuleName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteAlertRule(ctx, req.(*AlertRuleName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetSLOs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SLOQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetSLOs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_GetSLOs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetSLOs(ctx, req.(*SLOQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Nothing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Describe(ctx, req.(*Nothing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Admin_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "main.Admin",
 	HandlerType: (*AdminServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatistics",
+			Handler:    _Admin_GetStatistics_Handler,
+		},
+		{
+			MethodName: "TopK",
+			Handler:    _Admin_TopK_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _Admin_History_Handler,
+		},
+		{
+			MethodName: "ListAlertRules",
+			Handler:    _Admin_ListAlertRules_Handler,
+		},
+		{
+			MethodName: "SetAlertRule",
+			Handler:    _Admin_SetAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _Admin_DeleteAlertRule_Handler,
+		},
+		{
+			MethodName: "GetSLOs",
+			Handler:    _Admin_GetSLOs_Handler,
+		},
+		{
+			MethodName: "Describe",
+			Handler:    _Admin_Describe_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Logging",
@@ -168,6 +509,11 @@ var Admin_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Admin_Statistics_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Alerts",
+			Handler:       _Admin_Alerts_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "service.proto",
 }
@@ -2,19 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -558,3 +573,1051 @@ func __dummyLog() {
 	fmt.Println(1)
 	log.Println(1)
 }
+
+// интервал 0 не должен ронять сервер, а миллисекундный интервал должен работать
+func TestStatInterval(t *testing.T) {
+	ctx, finish := context.WithCancel(context.Background())
+	err := StartMyMicroservice(ctx, listenAddr, ACLData)
+	if err != nil {
+		t.Fatalf("cant start server initial: %v", err)
+	}
+	wait(1)
+	defer func() {
+		finish()
+		wait(1)
+	}()
+
+	conn := getGrpcConn(t)
+	defer conn.Close()
+
+	adm := NewAdminClient(conn)
+
+	for _, interval := range []*StatInterval{
+		{},
+		{IntervalMillis: 1},
+		{IntervalSeconds: 24 * 3600},
+	} {
+		stream, err := adm.Statistics(getConsumerCtx("stat1"), interval)
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if code := grpc.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %v, got %v", interval, err)
+		}
+	}
+
+	start := time.Now()
+	stream, err := adm.Statistics(getConsumerCtx("stat1"), &StatInterval{IntervalMillis: 200, Align: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stat, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Fatalf("first stat came after %v", elapsed)
+	}
+	if end := stat.GetWindowEnd().AsTime(); end.Truncate(200*time.Millisecond) != end {
+		t.Fatalf("window end %v is not aligned", end)
+	}
+}
+
+// при остановке потоки Admin получают последнее сообщение и закрываются без ошибки
+func TestGracefulShutdown(t *testing.T) {
+	ctx, finish := context.WithCancel(context.Background())
+	err := StartMyMicroservice(ctx, listenAddr, ACLData)
+	if err != nil {
+		t.Fatalf("cant start server initial: %v", err)
+	}
+	wait(1)
+	defer wait(1)
+
+	conn := getGrpcConn(t)
+	defer conn.Close()
+
+	biz := NewBizClient(conn)
+	adm := NewAdminClient(conn)
+
+	logStream, err := adm.Logging(getConsumerCtx("logger1"), &Nothing{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wait(1)
+	statStream, err := adm.Statistics(getConsumerCtx("stat1"), &StatInterval{IntervalSeconds: 3600})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wait(1)
+
+	biz.Check(getConsumerCtx("biz_user"), &Nothing{})
+	finish()
+
+	kinds := []EventKind{}
+	for {
+		evt, err := logStream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("logging stream must end cleanly, got %v", err)
+		}
+		kinds = append(kinds, evt.Kind)
+	}
+	// первое событие - подключение stat1
+	if !reflect.DeepEqual(kinds, []EventKind{EventKind_EVENT_KIND_CALL, EventKind_EVENT_KIND_CALL, EventKind_EVENT_KIND_SHUTDOWN}) {
+		t.Fatalf("bad events before shutdown: %v", kinds)
+	}
+
+	stat, err := statStream.Recv()
+	if err != nil {
+		t.Fatalf("expected final stat, got %v", err)
+	}
+	if stat.ByMethod["/main.Biz/Check"] != 1 {
+		t.Fatalf("bad final stat: %v", stat)
+	}
+	if _, err := statStream.Recv(); err != io.EOF {
+		t.Fatalf("statistics stream must end cleanly, got %v", err)
+	}
+}
+
+// порт 0, синхронная ошибка Listen и остановка через Shutdown
+func TestServerHandle(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData)
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	<-srv.Ready()
+	addr := srv.Addr().String()
+	if strings.HasSuffix(addr, ":0") {
+		t.Fatalf("expected real port, got %v", addr)
+	}
+
+	if _, err := NewServer(context.Background(), addr, ACLData); err == nil {
+		t.Fatalf("expected listen error on busy port")
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	_, err = NewBizClient(conn).Check(getConsumerCtx("biz_user"), &Nothing{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stat, err := srv.Stats().Query(&StatQuery{Range: StatRange_STAT_RANGE_SINCE_START}, time.Now())
+	if err != nil || stat.ByMethod["/main.Biz/Check"] != 1 {
+		t.Fatalf("call is not counted: %v, %v", stat, err)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if err := srv.Wait(); err != nil {
+		t.Fatalf("unexpected serve error: %v", err)
+	}
+}
+
+func TestParseACL(t *testing.T) {
+	for _, data := range []string{
+		`{"biz_user": ["Check"]}`,
+		`{"biz_user": ["/main.Biz"]}`,
+		`{"biz_user": ["/main.Biz/"]}`,
+	} {
+		if _, err := ParseACL(data); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+	acl, err := ParseACL(ACLData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, _ := acl.Allowed("biz_admin", "/main.Biz/Test"); !ok {
+		t.Errorf("biz_admin must be allowed to call Test by mask")
+	}
+	if ok, _ := acl.Allowed("biz_user", "/main.Biz/Test"); ok {
+		t.Errorf("biz_user must not be allowed to call Test")
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// встраивание без правки service.go: свой листенер, часы, определение потребителя и перехватчик
+func TestServerOptions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cant listen: %v", err)
+	}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	intercepted := make(chan string, 2)
+	srv, err := NewServer(context.Background(), "", ACLData,
+		WithListener(listener),
+		WithClock(fixedClock(now)),
+		WithIdentityResolver(func(ctx context.Context) (string, error) {
+			return "biz_user", nil
+		}),
+		WithExtraInterceptors([]grpc.UnaryServerInterceptor{
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				intercepted <- info.FullMethod
+				return handler(ctx, req)
+			},
+		}, nil),
+	)
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	if srv.Addr().String() != listener.Addr().String() {
+		t.Fatalf("server must serve given listener, got %v", srv.Addr())
+	}
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	logs := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(logs)
+
+	biz := NewBizClient(conn)
+	// без метаданных: потребителя определяет резолвер
+	if _, err := biz.Check(context.Background(), &Nothing{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Test не разрешён biz_user, до дополнительного перехватчика вызов не доходит
+	if _, err := biz.Test(context.Background(), &Nothing{}); grpc.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if len(intercepted) != 1 || <-intercepted != "/main.Biz/Check" {
+		t.Fatalf("extra interceptor must see only the allowed call")
+	}
+	if e := <-logs; e.Consumer != "biz_user" || !e.Time.AsTime().Equal(now) {
+		t.Fatalf("bad event: %v", e)
+	}
+}
+
+// проверки здоровья без consumer, без событий в журнале и NOT_SERVING при остановке
+func TestHealth(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData)
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	<-srv.Ready()
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	events := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(events)
+
+	hc := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", "main.Biz", "main.Admin"} {
+		resp, err := hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("service %q: expected SERVING, got %v, %v", service, resp, err)
+		}
+	}
+	if _, err := hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "main.Nope"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	watch, err := hc.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "main.Biz"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp, err := watch.Recv(); err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v, %v", resp, err)
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("health checks must not be logged, got %v", e)
+	default:
+	}
+
+	// Watch не должен задерживать остановку
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- srv.Shutdown(ctx)
+	}()
+	if resp, err := watch.Recv(); err != nil || resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING, got %v, %v", resp, err)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("shutdown waited for health watch: %v", err)
+	}
+}
+
+func TestHealthACL(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", `{"prober": ["/grpc.health.v1.Health/Check"]}`, WithHealthCheckACL(true))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	hc := healthpb.NewHealthClient(conn)
+	if _, err := hc.Check(context.Background(), &healthpb.HealthCheckRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if _, err := hc.Check(getConsumerCtx("prober"), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Describe и рефлексия под ACL
+func TestDescribe(t *testing.T) {
+	acl := `{
+	"ops":      ["/main.Admin/Describe", "/main.Admin/Logging", "/grpc.reflection.v1.ServerReflection/*"],
+	"biz_user": ["/main.Biz/Check"]
+}`
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", acl)
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	adm := NewAdminClient(conn)
+	_, err = adm.Logging(getConsumerCtx("ops"), &Nothing{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wait(1)
+
+	d, err := adm.Describe(getConsumerCtx("ops"), &Nothing{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.LoggingSubscribers != 1 || d.Version == "" || d.Started == nil {
+		t.Fatalf("bad description: %v", d)
+	}
+	methods := map[string]*MethodInfo{}
+	for _, s := range d.Services {
+		for _, m := range s.Methods {
+			methods[m.Name] = m
+		}
+	}
+	if m := methods["/main.Biz/Check"]; m == nil || !reflect.DeepEqual(m.Consumers, []string{"biz_user"}) {
+		t.Fatalf("bad /main.Biz/Check: %v", m)
+	}
+	if m := methods["/main.Admin/Logging"]; m == nil || !m.ServerStreaming || !reflect.DeepEqual(m.Consumers, []string{"ops"}) {
+		t.Fatalf("bad /main.Admin/Logging: %v", m)
+	}
+	if m := methods["/grpc.health.v1.Health/Check"]; m == nil || !m.Open {
+		t.Fatalf("health must be open: %v", m)
+	}
+
+	rc := reflectionpb.NewServerReflectionClient(conn)
+	list := func(consumer string) (*reflectionpb.ServerReflectionResponse, error) {
+		stream, err := rc.ServerReflectionInfo(getConsumerCtx(consumer))
+		if err != nil {
+			return nil, err
+		}
+		defer stream.CloseSend()
+		stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+		return stream.Recv()
+	}
+	if _, err := list("biz_user"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	resp, err := list("ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		found = found || s.Name == "main.Biz"
+	}
+	if !found {
+		t.Fatalf("main.Biz is not listed: %v", resp)
+	}
+}
+
+// вызовы через unix-сокет и второй TCP-адрес различаются по листенеру
+func TestListeners(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "hw7.sock")
+	addrs := []string{"unix:" + sock}
+	if l, err := net.Listen("tcp", "[::1]:0"); err == nil {
+		l.Close()
+		addrs = append(addrs, "[::1]:0")
+	}
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithListen(addrs...), WithSocketMode(0o600))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	<-srv.Ready()
+
+	fi, err := os.Stat(sock)
+	if err != nil || fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("bad socket file: %v, %v", fi, err)
+	}
+
+	events := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(events)
+
+	for _, addr := range srv.Addrs() {
+		target := formatAddr(addr)
+		conn, err := grpc.Dial(target, grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("cant connect to %s: %v", target, err)
+		}
+		_, err = NewBizClient(conn).Check(getConsumerCtx("biz_user"), &Nothing{})
+		conn.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", target, err)
+		}
+		e := <-events
+		if e.Listener != target {
+			t.Fatalf("expected listener %s, got %v", target, e)
+		}
+		// клиент на той же машине, поэтому его адрес отличается от листенера только портом
+		if tcp, ok := addr.(*net.TCPAddr); ok {
+			host, _, _ := net.SplitHostPort(e.Host)
+			if host != tcp.IP.String() || e.Host == target {
+				t.Fatalf("host must be the peer address, got %v", e)
+			}
+		}
+	}
+
+	// второй экземпляр не должен забрать сокет у работающего
+	if _, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithListen("unix:"+sock)); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("expected address in use, got %v", err)
+	}
+	if _, err := os.Stat(sock); err != nil {
+		t.Fatalf("socket of the running server is removed: %v", err)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Fatalf("socket file must be removed, got %v", err)
+	}
+
+	// файл от упавшего процесса заменяется
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: sock, Net: "unix"})
+	if err != nil {
+		t.Fatalf("cant create stale socket: %v", err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+	srv, err = NewServer(context.Background(), "127.0.0.1:0", ACLData, WithListen("unix:"+sock))
+	if err != nil {
+		t.Fatalf("stale socket must be replaced: %v", err)
+	}
+	srv.Shutdown(context.Background())
+}
+
+// unix-адреса по соглашению gRPC: относительный путь остаётся относительным
+func TestSocketPaths(t *testing.T) {
+	for _, c := range []struct {
+		addr, path string
+		valid      bool
+	}{
+		{"unix:run/hw7.sock", "run/hw7.sock", true},
+		{"unix:/run/hw7.sock", "/run/hw7.sock", true},
+		{"unix:///run/hw7.sock", "/run/hw7.sock", true},
+		{"unix://run/hw7.sock", "", false},
+		{"unix:", "", false},
+		{"unix:///", "/", false},
+		{"127.0.0.1:8082", "", true},
+	} {
+		path, _ := socketPath(c.addr)
+		if path != c.path || validListenAddr(c.addr) != c.valid {
+			t.Errorf("%s: got path %q, valid %v", c.addr, path, validListenAddr(c.addr))
+		}
+	}
+
+	wd, _ := os.Getwd()
+	dir := t.TempDir()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+	l, err := listen("unix:hw7.sock", 0o600)
+	if err != nil {
+		t.Fatalf("cant listen: %v", err)
+	}
+	defer l.Close()
+	if formatAddr(l.Addr()) != "unix:hw7.sock" {
+		t.Fatalf("bad listener address %v", l.Addr())
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "hw7.sock")); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("socket must be created in the working directory: %v, %v", fi, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("temporary directory is left behind: %v", entries)
+	}
+}
+
+// top-K через Admin при вытеснениях из маленькой сводки: тяжёлые ключи на месте,
+// а count отличается от истины не больше чем на error
+func TestAdminTopK(t *testing.T) {
+	acl := `{"admin": ["/main.Admin/*"], "biz_user": ["/main.Biz/*"], "biz_admin": ["/main.Biz/*"],
+		"c1": ["/main.Biz/*"], "c2": ["/main.Biz/*"], "c3": ["/main.Biz/*"], "c4": ["/main.Biz/*"]}`
+	cfg := DefaultStatsConfig()
+	cfg.TopK = 2
+	cfg.TopKCapacity = 3
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", acl, WithStatsConfig(cfg))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	biz := NewBizClient(conn)
+	calls := []struct {
+		consumer string
+		call     func(context.Context, *Nothing, ...grpc.CallOption) (*Nothing, error)
+		n        int
+	}{
+		{"biz_user", biz.Check, 20},
+		{"c1", biz.Test, 1},
+		{"biz_admin", biz.Add, 10},
+		{"c2", biz.Test, 1},
+		{"c3", biz.Test, 1},
+		{"c4", biz.Test, 1},
+	}
+	for _, c := range calls {
+		for i := 0; i < c.n; i++ {
+			if _, err := c.call(getConsumerCtx(c.consumer), &Nothing{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	reply, err := NewAdminClient(conn).TopK(getConsumerCtx("admin"), &TopKQuery{Range: StatRange_STAT_RANGE_SINCE_START})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// сам вызов TopK тоже учтён
+	if reply.Total != 35 || reply.MaxError > reply.Total/uint64(cfg.TopKCapacity) {
+		t.Fatalf("bad total or bound: %v", reply)
+	}
+	want := []struct {
+		consumer, method string
+		n                uint64
+	}{{"biz_user", "/main.Biz/Check", 20}, {"biz_admin", "/main.Biz/Add", 10}}
+	if len(reply.Consumers) != 2 || len(reply.Methods) != 2 {
+		t.Fatalf("expected top 2, got %v", reply)
+	}
+	for i, w := range want {
+		c, m := reply.Consumers[i], reply.Methods[i]
+		if c.Consumer != w.consumer || c.Count < w.n || c.Count-c.Error > w.n {
+			t.Errorf("bad consumer #%d: %v", i, c)
+		}
+		if m.Method != w.method || m.Count < w.n || m.Count-m.Error > w.n {
+			t.Errorf("bad method #%d: %v", i, m)
+		}
+	}
+}
+
+// лишнее соединение и слишком большое сообщение попадают в журнал
+// ответ больше предела на отправку журналируется и в унарном, и в потоковом вызове
+func TestSendSizeLimit(t *testing.T) {
+	acl := `{"admin": ["/main.Admin/*"]}`
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", acl, WithMaxMessageSize(0, 16))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+
+	events := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(events)
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	adm := NewAdminClient(conn)
+
+	_, err = adm.GetStatistics(getConsumerCtx("admin"), &StatQuery{})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	nextEvent(t, events)
+	e := nextEvent(t, events)
+	if e.Kind != EventKind_EVENT_KIND_MESSAGE_TOO_LARGE || e.Consumer != "admin" || e.Method != "/main.Admin/GetStatistics" {
+		t.Fatalf("bad event: %v", e)
+	}
+
+	stream, err := adm.Statistics(getConsumerCtx("admin"), &StatInterval{IntervalMillis: 100})
+	if err != nil {
+		t.Fatalf("cant start statistics: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	nextEvent(t, events)
+	e = nextEvent(t, events)
+	if e.Kind != EventKind_EVENT_KIND_MESSAGE_TOO_LARGE || e.Method != "/main.Admin/Statistics" {
+		t.Fatalf("bad event: %v", e)
+	}
+}
+
+func TestConnectionLimits(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithMaxConnections(1), WithMaxMessageSize(16, 0))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+
+	events := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(events)
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	if _, err := NewBizClient(conn).Check(getConsumerCtx("biz_user"), &Nothing{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nextEvent(t, events)
+
+	_, err = NewAdminClient(conn).SetAlertRule(getConsumerCtx("biz_user"), &AlertRule{Name: strings.Repeat("x", 100)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	e := nextEvent(t, events)
+	if e.Kind != EventKind_EVENT_KIND_MESSAGE_TOO_LARGE || e.Consumer != "biz_user" || e.Method != "/main.Admin/SetAlertRule" {
+		t.Fatalf("bad event: %v", e)
+	}
+
+	second, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer second.Close()
+	ctx, cancel := context.WithTimeout(getConsumerCtx("biz_user"), time.Second)
+	defer cancel()
+	if _, err := NewBizClient(second).Check(ctx, &Nothing{}); err == nil {
+		t.Fatalf("second connection must be rejected")
+	}
+	e = nextEvent(t, events)
+	if e.Kind != EventKind_EVENT_KIND_CONNECTION_REJECTED || e.Listener != srv.Addr().String() {
+		t.Fatalf("bad event: %v", e)
+	}
+
+	// наплыв отказов не превращается в поток событий
+	for i := 0; i < 5; i++ {
+		raw, err := net.Dial("tcp", srv.Addr().String())
+		if err != nil {
+			t.Fatalf("cant connect: %v", err)
+		}
+		io.Copy(io.Discard, raw)
+		raw.Close()
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("rejections must be rate limited, got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func nextEvent(t *testing.T, events chan *Event) *Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no event in 3 sec")
+		return nil
+	}
+}
+
+// подписчик Logging, у которого пропала сеть, отключается по keepalive,
+// а не висит до таймаута TCP
+func TestKeepaliveReapsDeadPeer(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithKeepalive(
+		keepalive.ServerParameters{Time: 100 * time.Millisecond, Timeout: 100 * time.Millisecond},
+		keepalive.EnforcementPolicy{},
+	))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+
+	addr, cut := startProxy(t, srv.Addr().String())
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	logger := srv.Logger().(*SimpleEventLogger)
+	before := logger.Subscribers()
+	ctx, cancel := context.WithCancel(getConsumerCtx("logger1"))
+	defer cancel()
+	if _, err := NewAdminClient(conn).Logging(ctx, &Nothing{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(t, func() bool { return logger.Subscribers() == before+1 })
+
+	cut()
+	waitFor(t, func() bool { return logger.Subscribers() == before })
+}
+
+// startProxy TCP-прокси к target; после cut он молча теряет данные в обе стороны,
+// не закрывая соединений, как оборвавшаяся сеть
+func startProxy(t *testing.T, target string) (addr string, cut func()) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cant listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	var dead atomic.Bool
+	pipe := func(dst, src net.Conn) {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if err != nil {
+				dst.Close()
+				return
+			}
+			if !dead.Load() {
+				dst.Write(buf[:n])
+			}
+		}
+	}
+	go func() {
+		for {
+			client, err := l.Accept()
+			if err != nil {
+				return
+			}
+			server, err := net.Dial("tcp", target)
+			if err != nil {
+				client.Close()
+				continue
+			}
+			t.Cleanup(func() { client.Close(); server.Close() })
+			go pipe(server, client)
+			go pipe(client, server)
+		}
+	}()
+	return l.Addr().String(), func() { dead.Store(true) }
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in 3 sec")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// stubStats собственная реализация EventStats, остальные методы не вызываются
+type stubStats struct {
+	EventStats
+	window  *StatWindow
+	records chan *Event
+}
+
+func (s *stubStats) Record(e *Event)                                     { s.records <- e }
+func (s *stubStats) Complete(e *Event, code codes.Code, d time.Duration) {}
+func (s *stubStats) Reject(e *Event, code codes.Code)                    {}
+func (s *stubStats) Unsubscribe(w *StatWindow)                           {}
+func (s *stubStats) Close() error                                        { return nil }
+
+func (s *stubStats) Subscribe(req *StatInterval) (*StatWindow, error) {
+	return s.window, nil
+}
+
+func (s *stubStats) Flush(w *StatWindow, now time.Time) *Stat {
+	return &Stat{ByMethod: map[string]uint64{"stub": 1}}
+}
+
+// recordingLogger свой журнал поверх SimpleEventLogger
+type recordingLogger struct {
+	*SimpleEventLogger
+	events chan *Event
+}
+
+func (l *recordingLogger) LogEvent(consumer, method, host, listener string, kind EventKind) *Event {
+	e := l.SimpleEventLogger.LogEvent(consumer, method, host, listener, kind)
+	l.events <- e
+	return e
+}
+
+func TestCustomStatsAndLogger(t *testing.T) {
+	stats := &stubStats{window: &StatWindow{}, records: make(chan *Event, 10)}
+	logger := &recordingLogger{SimpleEventLogger: NewSimpleEventLogger(NewSequencer(), SystemClock{}), events: make(chan *Event, 10)}
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithStats(stats), WithEventLogger(logger))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+
+	// окно без интервала не должно зациклить поток
+	ctx, cancel := context.WithTimeout(getConsumerCtx("stat1"), time.Second)
+	defer cancel()
+	stream, err := NewAdminClient(conn).Statistics(ctx, &StatInterval{IntervalSeconds: 1})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal for window without interval, got %v", err)
+	}
+	if e, s := <-logger.events, <-stats.records; e != s || e.Consumer != "stat1" {
+		t.Fatalf("custom logger and stats must see the same event: %v, %v", e, s)
+	}
+
+	stats.window = NewStatWindow(100*time.Millisecond, false)
+	stream, err = NewAdminClient(conn).Statistics(ctx, &StatInterval{IntervalSeconds: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st, err := stream.Recv(); err != nil || st.ByMethod["stub"] != 1 {
+		t.Fatalf("expected stub stat, got %v, %v", st, err)
+	}
+}
+
+// selfSignedTLS сертификат для 127.0.0.1 и пул, которому клиент доверяет
+func selfSignedTLS(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("cannot create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, pool
+}
+
+func TestServerTLS(t *testing.T) {
+	serverTLS, pool := selfSignedTLS(t)
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithTLS(serverTLS))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool})))
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	if _, err := NewBizClient(conn).Check(getConsumerCtx("biz_user"), &Nothing{}); err != nil {
+		t.Fatalf("unexpected error over TLS: %v", err)
+	}
+
+	plain, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer plain.Close()
+	ctx, cancel := context.WithTimeout(getConsumerCtx("biz_user"), time.Second)
+	defer cancel()
+	if _, err := NewBizClient(plain).Check(ctx, &Nothing{}); err == nil {
+		t.Fatalf("plaintext call must fail")
+	}
+}
+
+// несобравшийся сервер не оставляет за собой фоновое сохранение истории
+func TestNewServerErrorStopsHistory(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.HistoryPath = filepath.Join(t.TempDir(), "history.json")
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		if _, err := NewServer(context.Background(), "no port", ACLData, WithStatsConfig(cfg)); err == nil {
+			t.Fatalf("expected listen error")
+		}
+	}
+	if n := runtime.NumGoroutine(); n >= before+20 {
+		t.Fatalf("goroutines leaked: %d before, %d after", before, n)
+	}
+	if _, err := os.Stat(cfg.HistoryPath); !os.IsNotExist(err) {
+		t.Fatalf("failed server must not write history: %v", err)
+	}
+}
+
+// failingListener Accept возвращает ошибку, как только закрыт fail
+type failingListener struct {
+	net.Listener
+	fail chan struct{}
+}
+
+func (l *failingListener) Accept() (net.Conn, error) {
+	conns := make(chan net.Conn, 1)
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		conns <- conn
+	}()
+	select {
+	case conn := <-conns:
+		return conn, nil
+	case err := <-errs:
+		return nil, err
+	case <-l.fail:
+		return nil, fmt.Errorf("listener is broken")
+	}
+}
+
+// остановка после ошибки Serve ограничена shutdown timeout, даже если вызов завис
+func TestServeErrorShutdownTimeout(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cant listen: %v", err)
+	}
+	l := &failingListener{Listener: raw, fail: make(chan struct{})}
+	hung, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	block := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		close(hung)
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return handler(ctx, req)
+	}
+	srv, err := NewServer(context.Background(), "", ACLData, WithListener(l),
+		WithShutdownTimeout(100*time.Millisecond), WithExtraInterceptors([]grpc.UnaryServerInterceptor{block}, nil))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	<-srv.Ready()
+
+	conn, err := grpc.Dial(raw.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	go NewBizClient(conn).Check(getConsumerCtx("biz_user"), &Nothing{})
+	<-hung
+
+	close(l.fail)
+	done := make(chan error, 1)
+	go func() { done <- srv.Wait() }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "broken") {
+			t.Fatalf("expected serve error, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("shutdown after serve error hangs on an in-flight call")
+	}
+}
+
+// вызов без метаданных consumer учитывается как "unknown" с кодом Unauthenticated
+func TestUnknownConsumerRecorded(t *testing.T) {
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData)
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+	events := srv.Logger().Subscribe()
+	defer srv.Logger().Unsubscribe(events)
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	if _, err := NewBizClient(conn).Check(context.Background(), &Nothing{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	e := nextEvent(t, events)
+	if e.Consumer != unknownConsumer || e.Kind != EventKind_EVENT_KIND_DENIED || e.Method != "/main.Biz/Check" {
+		t.Fatalf("bad event: %v", e)
+	}
+	stat, err := srv.Stats().Query(&StatQuery{Range: StatRange_STAT_RANGE_SINCE_START}, time.Now())
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if n := stat.CodesByConsumer[unknownConsumer].GetByCode()["Unauthenticated"]; n != 1 {
+		t.Fatalf("expected 1 Unauthenticated call of unknown consumer, got %d: %v", n, stat.CodesByConsumer)
+	}
+	if stat.ByConsumer[unknownConsumer] != 1 {
+		t.Fatalf("unknown consumer is missing in by_consumer: %v", stat.ByConsumer)
+	}
+}
+
+// с фиксированными часами окна Statistics идут по реальному расписанию,
+// а отметки получают время часов
+func TestStatisticsFixedClock(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	srv, err := NewServer(context.Background(), "127.0.0.1:0", ACLData, WithClock(fixedClock(now)))
+	if err != nil {
+		t.Fatalf("cant start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+	<-srv.Ready()
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("cant connect to grpc: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(getConsumerCtx("stat1"), 3*time.Second)
+	defer cancel()
+	stream, err := NewAdminClient(conn).Statistics(ctx, &StatInterval{IntervalMillis: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		stat, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("window %d: %v", i, err)
+		}
+		if !stat.WindowEnd.AsTime().Equal(now) || !stat.Time.AsTime().Equal(now) {
+			t.Fatalf("window must be stamped by the server clock, got %v", stat.WindowEnd.AsTime())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// сколько по умолчанию ждать вызовы в работе, прежде чем остановить сервер принудительно
+const defaultShutdownTimeout = 10 * time.Second
+
+var errShuttingDown = status.Errorf(codes.Unavailable, "server is shutting down")
+
+// drainState признак остановки, общий для перехватчиков и потоков Admin
+type drainState struct {
+	draining atomic.Bool
+	inFlight atomic.Int64
+	done     chan struct{} // закрывается, когда потокам Admin пора попрощаться
+}
+
+func newDrainState() *drainState {
+	return &drainState{
+		done: make(chan struct{}),
+	}
+}
+
+// gracefulShutdown новые вызовы отклоняются, потоки Admin получают последнее сообщение
+// и закрываются, вызовы в работе дожидаются до отмены ctx, после чего сервер
+// останавливается принудительно. Возвращает false, если пришлось оборвать вызовы
+func gracefulShutdown(ctx context.Context, server *grpc.Server, drain *drainState, logger EventLogger, listener string) bool {
+	log.Printf("Shutting down: rejecting new calls, %d in flight", drain.inFlight.Load())
+	drain.draining.Store(true)
+	// событие уходит раньше закрытия done, поэтому Logging успевает его отправить
+	logger.LogEvent("", "", "", listener, EventKind_EVENT_KIND_SHUTDOWN)
+	close(drain.done)
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	progress := time.NewTicker(time.Second)
+	defer progress.Stop()
+	for {
+		select {
+		case <-stopped:
+			return true
+		case <-progress.C:
+			log.Printf("Shutting down: waiting for %d calls in flight", drain.inFlight.Load())
+		case <-ctx.Done():
+			log.Printf("Shutdown deadline exceeded, forcing stop with %d calls in flight", drain.inFlight.Load())
+			server.Stop()
+			<-stopped
+			return false
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	sloStep          = time.Minute
+	sloDefaultWindow = 30
+	sloMaxWindow     = 90 // дней, минутные корзины за 90 дней это около 2 МБ на SLO
+
+	fastBurnRate = 14.4
+	slowBurnRate = 6
+)
+
+// окна burn rate: пары 5m/1h и 30m/6h для быстрых и медленных сигналов, 1d и 3d для обзора
+var sloBurnWindows = []time.Duration{
+	5 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+}
+
+type sloBucket struct {
+	Start int64  `json:"start"`
+	Good  uint64 `json:"good"`
+	Total uint64 `json:"total"`
+}
+
+// sloSeries поминутные счётчики одного SLO за его окно
+type sloSeries struct {
+	slo     *SLO
+	latency time.Duration
+	started time.Time
+	buckets []sloBucket
+	// номера корзин, изменённых с прошлого сохранения
+	dirty map[int]bool
+}
+
+// sloTracker считает хорошие и все завершённые вызовы методов с объявленными SLO.
+// Отклонённые ACL вызовы до метода не дошли и в SLO не учитываются
+type sloTracker struct {
+	series   []*sloSeries
+	byMethod map[string][]*sloSeries
+}
+
+// sloChange копия корзины, изменённой с прошлого сохранения
+type sloChange struct {
+	series, index int
+	bucket        sloBucket
+}
+
+// sloSaved SLO в файле: непустые корзины и то, от чего зависит их смысл
+type sloSaved struct {
+	Name               string      `json:"name"`
+	Method             string      `json:"method"`
+	LatencyThresholdMs uint32      `json:"latency_threshold_ms"`
+	WindowDays         uint32      `json:"window_days"`
+	Started            int64       `json:"started"`
+	Buckets            []sloBucket `json:"buckets"`
+}
+
+// LoadSLOs читает JSON-файл с сообщением SLOs
+func LoadSLOs(path string) ([]*SLO, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	slos := &SLOs{}
+	if err := protojson.Unmarshal(data, slos); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, slo := range slos.GetSlos() {
+		if err := validateSLO(slo); err != nil {
+			return nil, err
+		}
+		if seen[slo.GetName()] {
+			return nil, fmt.Errorf("duplicate slo %q", slo.GetName())
+		}
+		seen[slo.GetName()] = true
+	}
+	return slos.GetSlos(), nil
+}
+
+func validateSLO(slo *SLO) error {
+	if slo.GetName() == "" || slo.GetMethod() == "" {
+		return fmt.Errorf("slo name and method are required")
+	}
+	if slo.GetObjective() <= 0 || slo.GetObjective() >= 1 {
+		return fmt.Errorf("slo %q: objective must be in (0, 1)", slo.GetName())
+	}
+	if slo.GetWindowDays() > sloMaxWindow {
+		return fmt.Errorf("slo %q: window_days must not exceed %d", slo.GetName(), sloMaxWindow)
+	}
+	return nil
+}
+
+func newSLOTracker(slos []*SLO, now time.Time) *sloTracker {
+	t := &sloTracker{
+		byMethod: make(map[string][]*sloSeries),
+	}
+	for _, slo := range slos {
+		// значения по умолчанию дописываются в копию, конфигурация вызывающего не меняется
+		slo = proto.Clone(slo).(*SLO)
+		if slo.WindowDays == 0 {
+			slo.WindowDays = sloDefaultWindow
+		}
+		s := &sloSeries{
+			slo:     slo,
+			latency: time.Duration(slo.GetLatencyThresholdMs()) * time.Millisecond,
+			started: now,
+			buckets: make([]sloBucket, int(sloWindow(slo)/sloStep)),
+		}
+		t.series = append(t.series, s)
+		t.byMethod[slo.GetMethod()] = append(t.byMethod[slo.GetMethod()], s)
+	}
+	return t
+}
+
+func sloWindow(slo *SLO) time.Duration {
+	return time.Duration(slo.GetWindowDays()) * 24 * time.Hour
+}
+
+func (t *sloTracker) complete(e *Event, code codes.Code, latency time.Duration, now time.Time) {
+	for _, s := range t.byMethod[e.Method] {
+		b := s.bucket(now)
+		b.Total++
+		if code == codes.OK && (s.latency == 0 || latency <= s.latency) {
+			b.Good++
+		}
+	}
+}
+
+func (s *sloSeries) bucket(now time.Time) *sloBucket {
+	step := int64(sloStep / time.Second)
+	start := now.Unix() - now.Unix()%step
+	i := int(start / step % int64(len(s.buckets)))
+	s.markDirty(i)
+	b := &s.buckets[i]
+	if b.Start != start {
+		*b = sloBucket{Start: start}
+	}
+	return b
+}
+
+// status счётчики за окно SLO и окна burn rate за один проход по корзинам.
+// Окна отсчитываются целыми минутами, текущая неполная минута входит во все
+func (s *sloSeries) status(now time.Time) *SLOStatus {
+	objective := s.slo.GetObjective()
+	window := sloWindow(s.slo)
+	st := &SLOStatus{
+		Slo:           s.slo,
+		TrackingSince: timestamppb.New(s.started),
+	}
+	for _, w := range sloBurnWindows {
+		st.BurnRates = append(st.BurnRates, &BurnRate{WindowSeconds: uint64(w / time.Second)})
+	}
+
+	current := now.Unix() - now.Unix()%int64(sloStep/time.Second)
+	for _, b := range s.buckets {
+		if b.Total == 0 || b.Start > current {
+			continue
+		}
+		age := time.Duration(current-b.Start) * time.Second
+		if age >= window {
+			continue
+		}
+		st.Total += b.Total
+		st.Good += b.Good
+		for i, w := range sloBurnWindows {
+			if age < w {
+				st.BurnRates[i].Total += b.Total
+				st.BurnRates[i].Bad += b.Total - b.Good
+			}
+		}
+	}
+
+	st.Compliance, st.BudgetRemaining = 1, 1
+	if st.Total > 0 {
+		st.Compliance = float64(st.Good) / float64(st.Total)
+		st.BudgetRemaining = 1 - float64(st.Total-st.Good)/(float64(st.Total)*(1-objective))
+	}
+	burn := make(map[time.Duration]float64)
+	for i, br := range st.BurnRates {
+		if br.Total > 0 {
+			br.Rate = float64(br.Bad) / float64(br.Total) / (1 - objective)
+		}
+		burn[sloBurnWindows[i]] = br.Rate
+	}
+	st.FastBurn = burn[time.Hour] > fastBurnRate && burn[5*time.Minute] > fastBurnRate
+	st.SlowBurn = burn[6*time.Hour] > slowBurnRate && burn[30*time.Minute] > slowBurnRate
+	return st
+}
+
+func (t *sloTracker) report(q *SLOQuery, now time.Time) (*SLOReport, error) {
+	names := stringSet(q.GetNames())
+	all := len(names) == 0
+	reply := &SLOReport{Time: timestamppb.New(now)}
+	for _, s := range t.series {
+		if all || names[s.slo.GetName()] {
+			reply.Slos = append(reply.Slos, s.status(now))
+			delete(names, s.slo.GetName())
+		}
+	}
+	for name := range names {
+		return nil, status.Errorf(codes.NotFound, "no slo %q", name)
+	}
+	return reply, nil
+}
+
+func (s *sloSeries) markDirty(i int) {
+	if s.dirty == nil {
+		s.dirty = make(map[int]bool)
+	}
+	s.dirty[i] = true
+}
+
+// changes копии корзин, изменённых с прошлого вызова, как statHistory.changes
+func (t *sloTracker) changes() []sloChange {
+	var res []sloChange
+	for si, s := range t.series {
+		for i := range s.dirty {
+			res = append(res, sloChange{series: si, index: i, bucket: s.buckets[i]})
+		}
+		s.dirty = nil
+	}
+	return res
+}
+
+// apply переносит изменения в копию для сохранения
+func (t *sloTracker) apply(changes []sloChange) {
+	for _, c := range changes {
+		t.series[c.series].buckets[c.index] = c.bucket
+	}
+}
+
+// marshal сохраняются только непустые корзины: место корзины в кольце следует из её начала,
+// а у SLO на 90 дней почти все корзины обычно пусты
+func (t *sloTracker) marshal() ([]byte, error) {
+	var saved []*sloSaved
+	for _, s := range t.series {
+		ss := &sloSaved{
+			Name:               s.slo.GetName(),
+			Method:             s.slo.GetMethod(),
+			LatencyThresholdMs: s.slo.GetLatencyThresholdMs(),
+			WindowDays:         s.slo.GetWindowDays(),
+			Started:            s.started.Unix(),
+		}
+		for _, b := range s.buckets {
+			if b.Total > 0 {
+				ss.Buckets = append(ss.Buckets, b)
+			}
+		}
+		saved = append(saved, ss)
+	}
+	return json.Marshal(saved)
+}
+
+// load корзины SLO, у которого с сохранения поменялись метод, порог задержки
+// или окно, пропускаются: их счётчики значили бы другое
+func (t *sloTracker) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var saved []*sloSaved
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	byName := make(map[string]*sloSaved, len(saved))
+	for _, ss := range saved {
+		byName[ss.Name] = ss
+	}
+	step := int64(sloStep / time.Second)
+	for _, s := range t.series {
+		ss := byName[s.slo.GetName()]
+		if ss == nil || ss.Method != s.slo.GetMethod() || ss.LatencyThresholdMs != s.slo.GetLatencyThresholdMs() ||
+			ss.WindowDays != s.slo.GetWindowDays() {
+			continue
+		}
+		s.started = time.Unix(ss.Started, 0)
+		for _, b := range ss.Buckets {
+			i := int(b.Start / step % int64(len(s.buckets)))
+			if b.Start%step == 0 && b.Start > s.buckets[i].Start {
+				s.buckets[i] = b
+				s.markDirty(i)
+			}
+		}
+	}
+	return nil
+}
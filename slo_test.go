@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestSLOBudget(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.SLOs = []*SLO{{Name: "add", Method: "/main.Biz/Add", Objective: 0.99, LatencyThresholdMs: 50}}
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+
+	call := func(method string, code codes.Code, latency time.Duration) {
+		e := &Event{Consumer: "biz_user", Method: method}
+		ss.Record(e)
+		ss.Complete(e, code, latency)
+	}
+	for i := 0; i < 98; i++ {
+		call("/main.Biz/Add", codes.OK, time.Millisecond)
+	}
+	call("/main.Biz/Add", codes.Internal, time.Millisecond)
+	call("/main.Biz/Add", codes.OK, 100*time.Millisecond)
+	call("/main.Biz/Check", codes.Internal, time.Millisecond)
+
+	report, err := ss.SLOs(&SLOQuery{}, time.Now())
+	if err != nil || len(report.Slos) != 1 {
+		t.Fatalf("bad report: %v, %v", report, err)
+	}
+	st := report.Slos[0]
+	if st.Total != 100 || st.Good != 98 || st.Slo.WindowDays != 30 {
+		t.Fatalf("bad counts: %v", st)
+	}
+	if cfg.SLOs[0].WindowDays != 0 {
+		t.Fatalf("caller's SLO was modified: %v", cfg.SLOs[0])
+	}
+	// 2 плохих из 100 при допустимом 1
+	if math.Abs(st.BudgetRemaining+1) > 1e-9 || math.Abs(st.BurnRates[0].Rate-2) > 1e-9 {
+		t.Fatalf("bad budget: %v", st)
+	}
+	if st.FastBurn || st.SlowBurn {
+		t.Fatalf("burn rate 2 must not page: %v", st)
+	}
+
+	for i := 0; i < 30; i++ {
+		call("/main.Biz/Add", codes.Unavailable, time.Millisecond)
+	}
+	report, _ = ss.SLOs(&SLOQuery{Names: []string{"add"}}, time.Now())
+	if st := report.Slos[0]; !st.FastBurn || !st.SlowBurn {
+		t.Fatalf("burn rate %v must page", st.BurnRates[0].Rate)
+	}
+
+	if _, err := ss.SLOs(&SLOQuery{Names: []string{"missing"}}, time.Now()); err == nil {
+		t.Fatalf("unknown slo must fail")
+	}
+}
+
+// корзины SLO переживают перезапуск вместе с историей, если SLO не поменялся
+func TestSLOPersist(t *testing.T) {
+	cfg := DefaultStatsConfig()
+	cfg.HistoryPath = filepath.Join(t.TempDir(), "history.json")
+	cfg.SLOs = []*SLO{{Name: "add", Method: "/main.Biz/Add", Objective: 0.99, LatencyThresholdMs: 50}}
+	ss := NewSimpleEventStats(NewSequencer(), cfg)
+	for _, code := range []codes.Code{codes.OK, codes.OK, codes.Internal} {
+		e := &Event{Consumer: "biz_user", Method: "/main.Biz/Add"}
+		ss.Record(e)
+		ss.Complete(e, code, time.Millisecond)
+	}
+	before, _ := ss.SLOs(&SLOQuery{}, time.Now())
+	if err := ss.Close(); err != nil {
+		t.Fatalf("cant save history: %v", err)
+	}
+
+	restored := NewSimpleEventStats(NewSequencer(), cfg)
+	defer restored.Close()
+	report, _ := restored.SLOs(&SLOQuery{}, time.Now())
+	st := report.Slos[0]
+	if st.Total != 3 || st.Good != 2 {
+		t.Fatalf("slo was not restored: %v", st)
+	}
+	if st.TrackingSince.AsTime().Unix() != before.Slos[0].TrackingSince.AsTime().Unix() {
+		t.Fatalf("tracking_since must survive restart: %v, %v", st.TrackingSince.AsTime(), before.Slos[0].TrackingSince.AsTime())
+	}
+
+	// с другим порогом задержки старые счётчики значили бы другое
+	cfg.SLOs = []*SLO{{Name: "add", Method: "/main.Biz/Add", Objective: 0.99, LatencyThresholdMs: 10}}
+	changed := NewSimpleEventStats(NewSequencer(), cfg)
+	defer changed.Close()
+	report, _ = changed.SLOs(&SLOQuery{}, time.Now())
+	if st := report.Slos[0]; st.Total != 0 {
+		t.Fatalf("changed slo must start over: %v", st)
+	}
+}
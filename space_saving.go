@@ -0,0 +1,148 @@
+package main
+
+import (
+	"container/heap"
+	"sort"
+)
+
+type heavyHitter struct {
+	key   string
+	count uint64
+	err   uint64 // на сколько count может превышать истинное значение
+	index int    // место в minHeap
+}
+
+// minHeap счётчики по возрастанию count, корень вытесняется первым
+type minHeap []*heavyHitter
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h minHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *minHeap) Push(x interface{}) {
+	hh := x.(*heavyHitter)
+	hh.index = len(*h)
+	*h = append(*h, hh)
+}
+
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	hh := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return hh
+}
+
+// spaceSaving приблизительный top-K (Metwally et al.): держит не больше capacity
+// счётчиков, при вытеснении новый ключ наследует минимальный счётчик как погрешность.
+// Погрешность любого счётчика не превышает total/capacity.
+// Минимальный счётчик берётся из кучи, поэтому add стоит O(log capacity)
+type spaceSaving struct {
+	capacity int
+	total    uint64
+	counters map[string]*heavyHitter
+	heap     minHeap
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		counters: make(map[string]*heavyHitter),
+	}
+}
+
+func (s *spaceSaving) add(key string, n uint64) {
+	s.total += n
+	if hh, ok := s.counters[key]; ok {
+		hh.count += n
+		heap.Fix(&s.heap, hh.index)
+		return
+	}
+	if len(s.counters) < s.capacity {
+		hh := &heavyHitter{key: key, count: n}
+		s.counters[key] = hh
+		heap.Push(&s.heap, hh)
+		return
+	}
+	if s.capacity <= 0 {
+		return
+	}
+	// вытесненный счётчик переиспользуется под новый ключ на том же месте кучи
+	min := s.heap[0]
+	delete(s.counters, min.key)
+	min.key, min.err = key, min.count
+	min.count += n
+	s.counters[key] = min
+	heap.Fix(&s.heap, 0)
+}
+
+// floor сколько мог набрать ключ, которого нет среди счётчиков
+func (s *spaceSaving) floor() uint64 {
+	if len(s.counters) == 0 || len(s.counters) < s.capacity {
+		return 0
+	}
+	return s.heap[0].count
+}
+
+// merge объединение двух сводок (Agarwal et al.): ключу, отсутствующему в одной
+// из них, добавляется её минимальный счётчик, затем остаются capacity крупнейших
+func (s *spaceSaving) merge(o *spaceSaving) {
+	if o.total == 0 {
+		return
+	}
+	sFloor, oFloor := s.floor(), o.floor()
+	merged := make(map[string]*heavyHitter, len(s.counters)+len(o.counters))
+	for key, hh := range s.counters {
+		merged[key] = &heavyHitter{key: key, count: hh.count, err: hh.err}
+	}
+	for key, hh := range o.counters {
+		if m, ok := merged[key]; ok {
+			m.count += hh.count
+			m.err += hh.err
+		} else {
+			merged[key] = &heavyHitter{key: key, count: hh.count + sFloor, err: hh.err + sFloor}
+		}
+	}
+	for key, m := range merged {
+		if _, ok := o.counters[key]; !ok {
+			m.count += oFloor
+			m.err += oFloor
+		}
+	}
+
+	s.total += o.total
+	s.counters = merged
+	if len(merged) > s.capacity {
+		top := s.top(s.capacity)
+		s.counters = make(map[string]*heavyHitter, len(top))
+		for _, hh := range top {
+			s.counters[hh.key] = hh
+		}
+	}
+	s.heap = make(minHeap, 0, len(s.counters))
+	for _, hh := range s.counters {
+		hh.index = len(s.heap)
+		s.heap = append(s.heap, hh)
+	}
+	heap.Init(&s.heap)
+}
+
+func (s *spaceSaving) top(k int) []*heavyHitter {
+	res := make([]*heavyHitter, 0, len(s.counters))
+	for _, hh := range s.counters {
+		res = append(res, hh)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].count != res[j].count {
+			return res[i].count > res[j].count
+		}
+		return res[i].key < res[j].key
+	})
+	if len(res) > k {
+		res = res[:k]
+	}
+	return res
+}
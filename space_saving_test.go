@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSpaceSavingHeavyHitters(t *testing.T) {
+	left, right := newSpaceSaving(10), newSpaceSaving(10)
+	// два тяжёлых ключа на фоне сотни редких
+	for i := 0; i < 1000; i++ {
+		left.add("hammer", 1)
+		right.add("noisy", 1)
+		if i%10 == 0 {
+			left.add(fmt.Sprintf("rare-%d", i), 1)
+			right.add(fmt.Sprintf("rare-%d", i+1), 1)
+		}
+	}
+
+	top := left.top(1)
+	if len(top) != 1 || top[0].key != "hammer" || top[0].count-top[0].err > 1000 || top[0].count < 1000 {
+		t.Fatalf("bad top of left: %+v", top)
+	}
+
+	left.merge(right)
+	top = left.top(2)
+	if len(top) != 2 {
+		t.Fatalf("bad merged top: %+v", top)
+	}
+	for _, hh := range top {
+		if hh.key != "hammer" && hh.key != "noisy" {
+			t.Errorf("unexpected heavy hitter %+v", hh)
+		}
+		if hh.count < 1000 || hh.err > left.total/uint64(left.capacity) {
+			t.Errorf("bound violated for %+v, total %d", hh, left.total)
+		}
+	}
+	if len(left.counters) > left.capacity {
+		t.Errorf("merged summary exceeds capacity: %d", len(left.counters))
+	}
+}
+
+// вытесняется всегда наименьший счётчик, и его значение переходит в погрешность
+func TestSpaceSavingEvictsMin(t *testing.T) {
+	s := newSpaceSaving(3)
+	s.add("a", 5)
+	s.add("b", 2)
+	s.add("c", 7)
+	s.add("b", 4)
+	s.add("d", 1)
+	if _, ok := s.counters["b"]; !ok {
+		t.Fatalf("b is not the smallest after growing: %+v", s.top(3))
+	}
+	if d := s.counters["d"]; d == nil || d.count != 6 || d.err != 5 {
+		t.Fatalf("d must replace a with its count as error: %+v", s.top(3))
+	}
+	if s.floor() != 6 {
+		t.Fatalf("expected floor 6, got %d", s.floor())
+	}
+}
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// сюда сливаются значения сверх лимита кардинальности
+const otherBucket = "other"
+
+// windowSpec что и в каких пределах считает окно
+type windowSpec struct {
+	maxConsumers int
+	groupBy      []GroupBy
+	maxGroups    int
+	mode         StatMode
+	window       time.Duration
+	interval     time.Duration
+	align        bool
+	topK         int
+	topCapacity  int
+	sketches     bool
+}
+
+// по этим измерениям группируют накопители с момента старта и кольцо,
+// из них пересобираются группы для CUMULATIVE и SLIDING
+var storeGroupBy = []GroupBy{GroupBy_GROUP_BY_CONSUMER, GroupBy_GROUP_BY_METHOD, GroupBy_GROUP_BY_CODE}
+
+func (spec windowSpec) groupByCode() bool {
+	for _, g := range spec.groupBy {
+		if g == GroupBy_GROUP_BY_CODE {
+			return true
+		}
+	}
+	return false
+}
+
+type groupCount struct {
+	values []string
+	count  uint64
+}
+
+// statAcc накопитель счётчиков за одно окно
+type statAcc struct {
+	spec              windowSpec
+	byMethod          map[string]uint64
+	byConsumer        map[string]uint64
+	byConsumerMethod  map[string]map[string]uint64
+	latencyByMethod   map[string]*latencySketch
+	codesByMethod     map[string]map[codes.Code]uint64
+	codesByConsumer   map[string]map[codes.Code]uint64
+	groups            map[string]*groupCount
+	topConsumers      *spaceSaving
+	topMethods        *spaceSaving
+	topPairs          *spaceSaving
+	consumersByMethod map[string]*hyperLogLog
+	// потребители фильтра, которые могли слиться в "other", см. filter
+	folded []string
+}
+
+func newStatAcc(spec windowSpec) *statAcc {
+	return &statAcc{
+		spec:              spec,
+		byMethod:          make(map[string]uint64),
+		byConsumer:        make(map[string]uint64),
+		byConsumerMethod:  make(map[string]map[string]uint64),
+		latencyByMethod:   make(map[string]*latencySketch),
+		codesByMethod:     make(map[string]map[codes.Code]uint64),
+		codesByConsumer:   make(map[string]map[codes.Code]uint64),
+		groups:            make(map[string]*groupCount),
+		topConsumers:      newSpaceSaving(spec.topCapacity),
+		topMethods:        newSpaceSaving(spec.topCapacity),
+		topPairs:          newSpaceSaving(spec.topCapacity),
+		consumersByMethod: make(map[string]*hyperLogLog),
+	}
+}
+
+func (a *statAcc) add(e *Event) {
+	a.byMethod[e.Method]++
+	a.byConsumer[capKey(a.byConsumer, e.Consumer, a.spec.maxConsumers)]++
+	a.addPair(e.Consumer, e.Method, 1)
+	a.topConsumers.add(e.Consumer, 1)
+	a.topMethods.add(e.Method, 1)
+	a.topPairs.add(pairKey(e.Consumer, e.Method), 1)
+	a.hll(e.Method).add(e.Consumer)
+
+	if len(a.spec.groupBy) > 0 && !a.spec.groupByCode() {
+		a.group(e, codes.OK)
+	}
+}
+
+// capKey ключ для счётчика по потребителю: новые потребители сверх max
+// попадают в "other". Вызов учитывается до проверки ACL, так что без предела
+// клиент со случайными consumer раздувал бы карты без границы
+func capKey[V any](m map[string]V, key string, max int) string {
+	if _, ok := m[key]; ok || len(m) < max {
+		return key
+	}
+	return otherBucket
+}
+
+func (a *statAcc) addPair(consumer, method string, n uint64) {
+	byMethod, ok := a.byConsumerMethod[consumer]
+	if !ok {
+		if len(a.byConsumerMethod) >= a.spec.maxConsumers {
+			consumer = otherBucket
+			byMethod = a.byConsumerMethod[consumer]
+		}
+		if byMethod == nil {
+			byMethod = make(map[string]uint64)
+			a.byConsumerMethod[consumer] = byMethod
+		}
+	}
+	byMethod[method] += n
+}
+
+func (a *statAcc) hll(method string) *hyperLogLog {
+	h, ok := a.consumersByMethod[method]
+	if !ok {
+		h = newHyperLogLog()
+		a.consumersByMethod[method] = h
+	}
+	return h
+}
+
+func (a *statAcc) complete(e *Event, code codes.Code, latency time.Duration) {
+	a.sketch(e.Method).add(latency.Seconds())
+	a.outcome(e, code)
+}
+
+func (a *statAcc) sketch(method string) *latencySketch {
+	sk, ok := a.latencyByMethod[method]
+	if !ok {
+		sk = newLatencySketch()
+		a.latencyByMethod[method] = sk
+	}
+	return sk
+}
+
+func (a *statAcc) outcome(e *Event, code codes.Code) {
+	countCode(a.codesByMethod, e.Method, code, 1)
+	countCode(a.codesByConsumer, capKey(a.codesByConsumer, e.Consumer, a.spec.maxConsumers), code, 1)
+
+	if a.spec.groupByCode() {
+		a.group(e, code)
+	}
+}
+
+func (a *statAcc) group(e *Event, code codes.Code) {
+	values := make([]string, len(a.spec.groupBy))
+	for i, g := range a.spec.groupBy {
+		switch g {
+		case GroupBy_GROUP_BY_CONSUMER:
+			values[i] = e.Consumer
+		case GroupBy_GROUP_BY_METHOD:
+			values[i] = e.Method
+		case GroupBy_GROUP_BY_PEER:
+			values[i] = e.Host
+		case GroupBy_GROUP_BY_LISTENER:
+			values[i] = e.Listener
+		case GroupBy_GROUP_BY_CODE:
+			values[i] = code.String()
+		}
+	}
+	a.addGroup(values, 1)
+}
+
+func (a *statAcc) addGroup(values []string, n uint64) {
+	key := strings.Join(values, "\x00")
+	gc, ok := a.groups[key]
+	if !ok && len(a.groups) >= a.spec.maxGroups {
+		// код остаётся настоящим: иначе свёрнутые OK читались бы как Unknown,
+		// то есть как ошибки. Групп "other" поэтому не больше, чем кодов
+		folded := make([]string, len(values))
+		for i, g := range a.spec.groupBy {
+			folded[i] = otherBucket
+			if g == GroupBy_GROUP_BY_CODE {
+				folded[i] = values[i]
+			}
+		}
+		values = folded
+		key = strings.Join(values, "\x00")
+		gc, ok = a.groups[key]
+	}
+	if !ok {
+		gc = &groupCount{values: values}
+		a.groups[key] = gc
+	}
+	gc.count += n
+}
+
+// merge добавляет к накопителю содержимое другого, лимиты берутся из a.spec
+func (a *statAcc) merge(b *statAcc) {
+	for method, n := range b.byMethod {
+		a.byMethod[method] += n
+	}
+	for consumer, n := range b.byConsumer {
+		a.byConsumer[capKey(a.byConsumer, consumer, a.spec.maxConsumers)] += n
+	}
+	for consumer, byMethod := range b.byConsumerMethod {
+		for method, n := range byMethod {
+			a.addPair(consumer, method, n)
+		}
+	}
+	for method, sk := range b.latencyByMethod {
+		a.sketch(method).merge(sk)
+	}
+	for method, byCode := range b.codesByMethod {
+		for code, n := range byCode {
+			countCode(a.codesByMethod, method, code, n)
+		}
+	}
+	for consumer, byCode := range b.codesByConsumer {
+		consumer = capKey(a.codesByConsumer, consumer, a.spec.maxConsumers)
+		for code, n := range byCode {
+			countCode(a.codesByConsumer, consumer, code, n)
+		}
+	}
+	for _, gc := range b.groups {
+		a.addGroup(gc.values, gc.count)
+	}
+	a.topConsumers.merge(b.topConsumers)
+	a.topMethods.merge(b.topMethods)
+	a.topPairs.merge(b.topPairs)
+	for method, h := range b.consumersByMethod {
+		a.hll(method).merge(h)
+	}
+}
+
+// regroup пересобирает группы накопителя хранилища (storeGroupBy) под измерения spec
+// и берёт из spec параметры вывода.
+// Без кода группы считаются по началу вызова из byConsumerMethod, с кодом - по завершению
+func (a *statAcc) regroup(spec windowSpec) {
+	from := a.spec.groupBy
+	groups := a.groups
+	a.spec.groupBy = spec.groupBy
+	a.spec.maxGroups = spec.maxGroups
+	a.spec.topK = spec.topK
+	a.spec.sketches = spec.sketches
+	a.groups = make(map[string]*groupCount)
+
+	if len(spec.groupBy) == 0 {
+		return
+	}
+	if !spec.groupByCode() {
+		for consumer, byMethod := range a.byConsumerMethod {
+			for method, n := range byMethod {
+				values := make([]string, len(spec.groupBy))
+				for i, g := range spec.groupBy {
+					if g == GroupBy_GROUP_BY_CONSUMER {
+						values[i] = consumer
+					} else {
+						values[i] = method
+					}
+				}
+				a.addGroup(values, n)
+			}
+		}
+		return
+	}
+
+	pos := make(map[GroupBy]int, len(from))
+	for i, g := range from {
+		pos[g] = i
+	}
+	for _, gc := range groups {
+		values := make([]string, len(spec.groupBy))
+		for i, g := range spec.groupBy {
+			values[i] = gc.values[pos[g]]
+		}
+		a.addGroup(values, gc.count)
+	}
+}
+
+// filter копия накопителя хранилища, в которой остались только указанные
+// потребители и методы (пустое множество - без ограничений).
+// Время выполнения хранится только по методам, поэтому фильтр по потребителю его не сужает.
+// Потребитель сверх лимита хранится только внутри "other", такие попадают в res.folded
+func (a *statAcc) filter(consumers, methods map[string]bool) *statAcc {
+	match := func(set map[string]bool, v string) bool {
+		return len(set) == 0 || set[v]
+	}
+	res := newStatAcc(a.spec)
+	if _, ok := a.byConsumer[otherBucket]; ok {
+		for consumer := range consumers {
+			if _, ok := a.byConsumer[consumer]; !ok {
+				res.folded = append(res.folded, consumer)
+			}
+		}
+		sort.Strings(res.folded)
+	}
+
+	for consumer, byMethod := range a.byConsumerMethod {
+		if !match(consumers, consumer) {
+			continue
+		}
+		for method, n := range byMethod {
+			if match(methods, method) {
+				res.addPair(consumer, method, n)
+			}
+		}
+	}
+	if len(consumers) == 0 {
+		for method, n := range a.byMethod {
+			if match(methods, method) {
+				res.byMethod[method] = n
+			}
+		}
+	} else {
+		for _, byMethod := range res.byConsumerMethod {
+			for method, n := range byMethod {
+				res.byMethod[method] += n
+			}
+		}
+	}
+	if len(methods) == 0 {
+		for consumer, n := range a.byConsumer {
+			if match(consumers, consumer) {
+				res.byConsumer[consumer] = n
+			}
+		}
+	} else {
+		for consumer, byMethod := range res.byConsumerMethod {
+			for _, n := range byMethod {
+				res.byConsumer[consumer] += n
+			}
+		}
+	}
+
+	for method, sk := range a.latencyByMethod {
+		if match(methods, method) {
+			res.sketch(method).merge(sk)
+		}
+	}
+
+	// группы хранилища: consumer, method, code
+	for _, gc := range a.groups {
+		consumer, method, code := gc.values[0], gc.values[1], gc.values[2]
+		if !match(consumers, consumer) || !match(methods, method) {
+			continue
+		}
+		res.addGroup(gc.values, gc.count)
+		if len(consumers) > 0 {
+			countCode(res.codesByMethod, method, parseCode(code), gc.count)
+		}
+		if len(methods) > 0 {
+			countCode(res.codesByConsumer, consumer, parseCode(code), gc.count)
+		}
+	}
+	if len(consumers) == 0 {
+		for method, byCode := range a.codesByMethod {
+			if match(methods, method) {
+				for code, n := range byCode {
+					countCode(res.codesByMethod, method, code, n)
+				}
+			}
+		}
+	}
+	if len(methods) == 0 {
+		for consumer, byCode := range a.codesByConsumer {
+			if match(consumers, consumer) {
+				for code, n := range byCode {
+					countCode(res.codesByConsumer, consumer, code, n)
+				}
+			}
+		}
+	}
+
+	if len(consumers) == 0 {
+		for method, h := range a.consumersByMethod {
+			if match(methods, method) {
+				res.hll(method).merge(h)
+			}
+		}
+	} else {
+		for consumer, byMethod := range res.byConsumerMethod {
+			for method := range byMethod {
+				res.hll(method).add(consumer)
+			}
+		}
+	}
+
+	if len(consumers) == 0 && len(methods) == 0 {
+		res.topConsumers.merge(a.topConsumers)
+		res.topMethods.merge(a.topMethods)
+		res.topPairs.merge(a.topPairs)
+	} else {
+		// после фильтра точные счётчики уже посчитаны, сводки строятся из них заново
+		for consumer, n := range res.byConsumer {
+			res.topConsumers.add(consumer, n)
+		}
+		for method, n := range res.byMethod {
+			res.topMethods.add(method, n)
+		}
+		for consumer, byMethod := range res.byConsumerMethod {
+			for method, n := range byMethod {
+				res.topPairs.add(pairKey(consumer, method), n)
+			}
+		}
+	}
+	return res
+}
+
+// parseCode обратное к codes.Code.String()
+func parseCode(s string) codes.Code {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == s {
+			return c
+		}
+	}
+	return codes.Unknown
+}
+
+// groupLabel имя измерения в GroupCount.labels: GROUP_BY_CONSUMER -> consumer
+func groupLabel(g GroupBy) string {
+	return strings.ToLower(strings.TrimPrefix(g.String(), "GROUP_BY_"))
+}
+
+func countCode(m map[string]map[codes.Code]uint64, key string, code codes.Code, n uint64) {
+	byCode, ok := m[key]
+	if !ok {
+		byCode = make(map[codes.Code]uint64)
+		m[key] = byCode
+	}
+	byCode[code] += n
+}
+
+func (a *statAcc) errorRatio(method string) float64 {
+	var total, failed uint64
+	for code, n := range a.codesByMethod[method] {
+		total += n
+		if code != codes.OK {
+			failed += n
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
+
+func (a *statAcc) toStat() *Stat {
+	st := &Stat{
+		ByMethod:           a.byMethod,
+		ByConsumer:         a.byConsumer,
+		LatencyByMethod:    make(map[string]*LatencyStat, len(a.latencyByMethod)),
+		CodesByMethod:      codesToProto(a.codesByMethod),
+		CodesByConsumer:    codesToProto(a.codesByConsumer),
+		ErrorRatioByMethod: make(map[string]float64, len(a.codesByMethod)),
+		ByConsumerMethod:   make(map[string]*MethodCounts, len(a.byConsumerMethod)),
+		Groups:             make([]*GroupCount, 0, len(a.groups)),
+		FoldedConsumers:    a.folded,
+	}
+	for consumer, byMethod := range a.byConsumerMethod {
+		st.ByConsumerMethod[consumer] = &MethodCounts{ByMethod: byMethod}
+	}
+	for _, gc := range a.groups {
+		labels := make(map[string]string, len(gc.values))
+		for i, g := range a.spec.groupBy {
+			labels[groupLabel(g)] = gc.values[i]
+		}
+		st.Groups = append(st.Groups, &GroupCount{Labels: labels, Count: gc.count})
+	}
+	sort.Slice(st.Groups, func(i, j int) bool {
+		return st.Groups[i].Count > st.Groups[j].Count
+	})
+	for method, sk := range a.latencyByMethod {
+		st.LatencyByMethod[method] = sk.toProto()
+	}
+	for method := range a.codesByMethod {
+		st.ErrorRatioByMethod[method] = a.errorRatio(method)
+	}
+	st.TopConsumers, st.TopMethods, st.TopPairs = a.heavyHitters(a.spec.topK)
+	st.UniqueConsumersByMethod = make(map[string]uint64, len(a.consumersByMethod))
+	for method, h := range a.consumersByMethod {
+		st.UniqueConsumersByMethod[method] = h.estimate()
+	}
+	if a.spec.sketches {
+		st.ConsumerSketchByMethod = make(map[string][]byte, len(a.consumersByMethod))
+		for method, h := range a.consumersByMethod {
+			st.ConsumerSketchByMethod[method] = h.marshal()
+		}
+	}
+	return st
+}
+
+func (a *statAcc) heavyHitters(k int) (consumers, methods, pairs []*HeavyHitter) {
+	for _, hh := range a.topConsumers.top(k) {
+		consumers = append(consumers, &HeavyHitter{Consumer: hh.key, Count: hh.count, Error: hh.err})
+	}
+	for _, hh := range a.topMethods.top(k) {
+		methods = append(methods, &HeavyHitter{Method: hh.key, Count: hh.count, Error: hh.err})
+	}
+	for _, hh := range a.topPairs.top(k) {
+		consumer, method, _ := strings.Cut(hh.key, "\x00")
+		pairs = append(pairs, &HeavyHitter{Consumer: consumer, Method: method, Count: hh.count, Error: hh.err})
+	}
+	return consumers, methods, pairs
+}
+
+func pairKey(consumer, method string) string {
+	return consumer + "\x00" + method
+}
+
+func codesToProto(m map[string]map[codes.Code]uint64) map[string]*CodeCounts {
+	res := make(map[string]*CodeCounts, len(m))
+	for key, byCode := range m {
+		cc := &CodeCounts{ByCode: make(map[string]uint64, len(byCode))}
+		for code, n := range byCode {
+			cc.ByCode[code.String()] = n
+		}
+		res[key] = cc
+	}
+	return res
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// разрешение и глубина истории: посекундно за час, поминутно за сутки, почасово за месяц
+var historyTiers = []struct {
+	step time.Duration
+	keep time.Duration
+}{
+	{time.Second, time.Hour},
+	{time.Minute, 24 * time.Hour},
+	{time.Hour, 30 * 24 * time.Hour},
+}
+
+type seriesPoint struct {
+	Calls        uint64  `json:"calls"`
+	Errors       uint64  `json:"errors"`
+	LatencySum   float64 `json:"latency_sum"`
+	LatencyCount uint64  `json:"latency_count"`
+}
+
+type historyBucket struct {
+	Start      int64                   `json:"start"`
+	ByMethod   map[string]*seriesPoint `json:"by_method"`
+	ByConsumer map[string]*seriesPoint `json:"by_consumer"`
+}
+
+// historyTier кольцо корзин одного разрешения
+type historyTier struct {
+	Step    int64           `json:"step"`
+	Buckets []historyBucket `json:"buckets"`
+	// номера корзин, изменённых с прошлого сохранения
+	dirty map[int]bool
+}
+
+// historyChange копия корзины, изменённой с прошлого сохранения
+type historyChange struct {
+	tier, index int
+	bucket      historyBucket
+}
+
+// statHistory свёртки статистики для запросов History.
+// Каждое событие сразу пишется во все разрешения, память ограничена размером колец
+// и maxConsumers на корзину, см. StatsConfig.HistoryMaxConsumers
+type statHistory struct {
+	maxConsumers int
+	tiers        []*historyTier
+}
+
+func newStatHistory(maxConsumers int) *statHistory {
+	h := &statHistory{maxConsumers: maxConsumers}
+	for _, t := range historyTiers {
+		h.tiers = append(h.tiers, &historyTier{
+			Step:    int64(t.step / time.Second),
+			Buckets: make([]historyBucket, int(t.keep/t.step)),
+		})
+	}
+	return h
+}
+
+func (t *historyTier) bucket(now time.Time) *historyBucket {
+	start := now.Unix() - now.Unix()%t.Step
+	i := int(start / t.Step % int64(len(t.Buckets)))
+	t.markDirty(i)
+	b := &t.Buckets[i]
+	if b.ByMethod == nil || b.Start != start {
+		*b = historyBucket{
+			Start:      start,
+			ByMethod:   make(map[string]*seriesPoint),
+			ByConsumer: make(map[string]*seriesPoint),
+		}
+	}
+	return b
+}
+
+func (h *statHistory) points(b *historyBucket, e *Event) (byMethod, byConsumer *seriesPoint) {
+	byMethod, ok := b.ByMethod[e.Method]
+	if !ok {
+		byMethod = &seriesPoint{}
+		b.ByMethod[e.Method] = byMethod
+	}
+	consumer := e.Consumer
+	byConsumer, ok = b.ByConsumer[consumer]
+	if !ok {
+		if len(b.ByConsumer) >= h.maxConsumers {
+			consumer = otherBucket
+			byConsumer = b.ByConsumer[consumer]
+		}
+		if byConsumer == nil {
+			byConsumer = &seriesPoint{}
+			b.ByConsumer[consumer] = byConsumer
+		}
+	}
+	return byMethod, byConsumer
+}
+
+func (h *statHistory) add(e *Event, now time.Time) {
+	for _, t := range h.tiers {
+		m, c := h.points(t.bucket(now), e)
+		m.Calls++
+		c.Calls++
+	}
+}
+
+func (h *statHistory) outcome(e *Event, code codes.Code, latency time.Duration, handled bool, now time.Time) {
+	for _, t := range h.tiers {
+		m, c := h.points(t.bucket(now), e)
+		for _, p := range []*seriesPoint{m, c} {
+			if code != codes.OK {
+				p.Errors++
+			}
+			if handled {
+				p.LatencySum += latency.Seconds()
+				p.LatencyCount++
+			}
+		}
+	}
+}
+
+// query ряд для потребителя или метода за [from, to] в самом подробном разрешении,
+// которое ещё хранит from
+func (h *statHistory) query(q *HistoryQuery, now time.Time) (*HistoryReply, error) {
+	if (q.GetConsumer() == "") == (q.GetMethod() == "") {
+		return nil, status.Errorf(codes.InvalidArgument, "exactly one of consumer and method must be set")
+	}
+	if q.GetFrom() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "from is required")
+	}
+	from, to := q.GetFrom().AsTime(), now
+	if q.GetTo() != nil && q.GetTo().AsTime().Before(now) {
+		to = q.GetTo().AsTime()
+	}
+	if to.Before(from) {
+		return nil, status.Errorf(codes.InvalidArgument, "to is before from")
+	}
+
+	tier := h.tiers[len(h.tiers)-1]
+	for _, t := range h.tiers {
+		if now.Unix()-from.Unix() < t.Step*int64(len(t.Buckets)) {
+			tier = t
+			break
+		}
+	}
+	// старше самой грубой истории ничего нет
+	if oldest := now.Unix() - tier.Step*int64(len(tier.Buckets)-1); from.Unix() < oldest {
+		from = time.Unix(oldest, 0)
+	}
+
+	reply := &HistoryReply{StepSeconds: uint64(tier.Step)}
+	first := from.Unix() - from.Unix()%tier.Step
+	for start := first; start <= to.Unix(); start += tier.Step {
+		b := &tier.Buckets[int(start/tier.Step%int64(len(tier.Buckets)))]
+		point := &HistoryPoint{Time: timestamppb.New(time.Unix(start, 0))}
+		if b.Start == start && b.ByMethod != nil {
+			var p *seriesPoint
+			if q.GetMethod() != "" {
+				p = b.ByMethod[q.GetMethod()]
+			} else {
+				p = b.ByConsumer[q.GetConsumer()]
+			}
+			if p != nil {
+				point.Calls = p.Calls
+				point.Errors = p.Errors
+				if p.LatencyCount > 0 {
+					point.LatencyAvg = p.LatencySum / float64(p.LatencyCount)
+				}
+			}
+		}
+		reply.Points = append(reply.Points, point)
+	}
+	return reply, nil
+}
+
+func (t *historyTier) markDirty(i int) {
+	if t.dirty == nil {
+		t.dirty = make(map[int]bool)
+	}
+	t.dirty[i] = true
+}
+
+// changes копии корзин, изменённых с прошлого вызова. Вызывается под блокировкой
+// статистики, поэтому копируется не больше, чем успело измениться
+func (h *statHistory) changes() []historyChange {
+	var res []historyChange
+	for ti, t := range h.tiers {
+		for i := range t.dirty {
+			res = append(res, historyChange{tier: ti, index: i, bucket: t.Buckets[i].clone()})
+		}
+		t.dirty = nil
+	}
+	return res
+}
+
+// apply переносит изменения в копию для сохранения
+func (h *statHistory) apply(changes []historyChange) {
+	for _, c := range changes {
+		h.tiers[c.tier].Buckets[c.index] = c.bucket
+	}
+}
+
+func (b historyBucket) clone() historyBucket {
+	copyPoints := func(m map[string]*seriesPoint) map[string]*seriesPoint {
+		if m == nil {
+			return nil
+		}
+		res := make(map[string]*seriesPoint, len(m))
+		for k, p := range m {
+			pc := *p
+			res[k] = &pc
+		}
+		return res
+	}
+	return historyBucket{Start: b.Start, ByMethod: copyPoints(b.ByMethod), ByConsumer: copyPoints(b.ByConsumer)}
+}
+
+func (h *statHistory) marshal() ([]byte, error) {
+	return json.Marshal(h.tiers)
+}
+
+func writeHistory(path string, data []byte) error {
+	// через временный файл, чтобы оборванная запись не испортила прошлую копию
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (h *statHistory) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var tiers []*historyTier
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return err
+	}
+	// корзины из файла с другим разрешением или глубиной пропускаются;
+	// загруженные считаются изменёнными, чтобы попасть в копию для сохранения
+	for i, t := range tiers {
+		if i < len(h.tiers) && t.Step == h.tiers[i].Step && len(t.Buckets) == len(h.tiers[i].Buckets) {
+			h.tiers[i] = t
+			for j := range t.Buckets {
+				if t.Buckets[j].ByMethod != nil {
+					t.markDirty(j)
+				}
+			}
+		}
+	}
+	return nil
+}
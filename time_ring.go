@@ -0,0 +1,50 @@
+package main
+
+import "time"
+
+type ringSlot struct {
+	start int64 // начало слота, unix-секунды
+	acc   *statAcc
+}
+
+// timeRing посекундные накопители за последние len(slots) секунд.
+// Старые слоты переиспользуются по кругу, поэтому память ограничена размером кольца
+type timeRing struct {
+	spec  windowSpec
+	slots []ringSlot
+}
+
+func newTimeRing(spec windowSpec, size time.Duration) *timeRing {
+	n := int(size / time.Second)
+	if n < 1 {
+		n = 1
+	}
+	return &timeRing{
+		spec:  spec,
+		slots: make([]ringSlot, n),
+	}
+}
+
+// slot накопитель для секунды, в которую попадает now
+func (r *timeRing) slot(now time.Time) *statAcc {
+	sec := now.Unix()
+	s := &r.slots[int(sec%int64(len(r.slots)))]
+	if s.acc == nil || s.start != sec {
+		s.start = sec
+		s.acc = newStatAcc(r.spec)
+	}
+	return s.acc
+}
+
+// since сумма слотов за последние d, считая секунду now
+func (r *timeRing) since(now time.Time, d time.Duration) *statAcc {
+	res := newStatAcc(r.spec)
+	to := now.Unix()
+	from := to - int64(d/time.Second)
+	for _, s := range r.slots {
+		if s.acc != nil && s.start > from && s.start <= to {
+			res.merge(s.acc)
+		}
+	}
+	return res
+}